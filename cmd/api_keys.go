@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/barpav/demography/internal/data"
+	"github.com/barpav/demography/internal/rest"
+	"github.com/barpav/demography/internal/rest/models"
+)
+
+const apiKeysUsage = `Usage:
+  app api-key create <name> <scope>...  create key (shown only once)
+  app api-key revoke <name>             revoke active key
+  app api-key list                      list all keys
+
+Scopes: ` + rest.ScopePeopleRead + `, ` + rest.ScopePeopleWrite + `, ` + rest.ScopePeopleDelete
+
+// Administration of API keys, returns exit code.
+func manageAPIKeys(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, apiKeysUsage)
+		return 2
+	}
+
+	storage := &data.Storage{}
+	err := storage.Open()
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to open storage: %s\n", err)
+		return 1
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	defer storage.Close(ctx)
+
+	switch args[0] {
+	case "create":
+		err = createAPIKey(ctx, storage, args[1:])
+	case "revoke":
+		err = revokeAPIKey(ctx, storage, args[1:])
+	case "list":
+		err = listAPIKeys(ctx, storage)
+	default:
+		fmt.Fprintln(os.Stderr, apiKeysUsage)
+		return 2
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	return 0
+}
+
+func createAPIKey(ctx context.Context, storage *data.Storage, args []string) error {
+	if len(args) < 2 {
+		return fmt.Errorf("Name and at least one scope must be specified.\n\n%s", apiKeysUsage)
+	}
+
+	apiKey := &models.APIKey{Name: args[0], Scopes: args[1:]}
+
+	for _, scope := range apiKey.Scopes {
+		if !rest.ValidScope(scope) {
+			return fmt.Errorf("Unknown scope '%s'.\n\n%s", scope, apiKeysUsage)
+		}
+	}
+
+	key, hash, err := rest.NewAPIKey()
+
+	if err != nil {
+		return err
+	}
+
+	err = storage.CreateAPIKey(ctx, apiKey, hash)
+
+	if err != nil {
+		return fmt.Errorf("Failed to create API key '%s': %w", apiKey.Name, err)
+	}
+
+	fmt.Println(key)
+
+	return nil
+}
+
+func revokeAPIKey(ctx context.Context, storage *data.Storage, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("Name of the key must be specified.\n\n%s", apiKeysUsage)
+	}
+
+	err := storage.RevokeAPIKey(ctx, args[0])
+
+	if err != nil {
+		return fmt.Errorf("Failed to revoke API key '%s': %w", args[0], err)
+	}
+
+	return nil
+}
+
+func listAPIKeys(ctx context.Context, storage *data.Storage) error {
+	keys, err := storage.APIKeys(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	for _, k := range keys {
+		status := "active"
+
+		if !k.RevokedAt.IsZero() {
+			status = "revoked " + k.RevokedAt.UTC().Format(time.RFC3339)
+		}
+
+		fmt.Printf("%s\t%s\tcreated %s\t%s\n", k.Name, strings.Join(k.Scopes, ","), k.CreatedAt.UTC().Format(time.RFC3339), status)
+	}
+
+	return nil
+}
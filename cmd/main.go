@@ -13,6 +13,7 @@ import (
 	"github.com/rs/zerolog/log"
 
 	"github.com/barpav/demography/internal/data"
+	"github.com/barpav/demography/internal/management"
 	"github.com/barpav/demography/internal/rest"
 	"github.com/barpav/demography/internal/statistics"
 )
@@ -23,10 +24,15 @@ const defaultLogLevel = zerolog.InfoLevel
 func main() {
 	setGlobalLogLevel()
 
+	if len(os.Args) > 1 && os.Args[1] == "api-key" {
+		os.Exit(manageAPIKeys(os.Args[2:]))
+	}
+
 	app := microservice{}
 	err := app.launch()
 
 	if err == nil {
+		app.serving = true
 		log.Info().Msg("Microservice launched.")
 	} else {
 		log.Err(err).Msg("Failed to launch microservice.")
@@ -44,10 +50,12 @@ func main() {
 
 type microservice struct {
 	api struct {
-		public *rest.Service // specification: https://barpav.github.io/demography-api/#/people
+		public     *rest.Service // specification: https://barpav.github.io/demography-api/#/people
+		management *management.Service
 	}
 	storage  *data.Storage
 	shutdown chan os.Signal
+	serving  bool // launched successfully
 }
 
 func (m *microservice) launch() (err error) {
@@ -57,8 +65,17 @@ func (m *microservice) launch() (err error) {
 	m.storage = &data.Storage{}
 	err = m.storage.Open()
 
+	if err == nil {
+		m.storage.StartPurging()
+	}
+
+	stats := &statistics.Provider{}
+
 	m.api.public = &rest.Service{}
-	m.api.public.Start(m.storage, &statistics.Provider{})
+	err = errors.Join(err, m.api.public.Start(m.storage, stats))
+
+	m.api.management = &management.Service{}
+	m.api.management.Start(m.storage, stats)
 
 	return err
 }
@@ -68,18 +85,32 @@ func (m *microservice) abort() {
 }
 
 func (m *microservice) serveAndShutdownGracefully() (err error) {
+	var stopped bool // by signal while serving requests
+
 	select {
 	case <-m.shutdown:
+		stopped = m.serving
 	case <-m.api.public.Shutdown():
+	case <-m.api.management.Shutdown():
 	}
 
 	log.Info().Msg("Shutting down...")
+	m.api.management.Drain()
+
+	// requests are still routed to the microservice until load balancers notice failing readiness
+	if stopped {
+		select {
+		case <-time.After(m.api.management.DrainPeriod()):
+		case <-m.shutdown: // repeated signal
+		}
+	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	err = errors.Join(err, m.api.public.Stop(ctx))
 	err = errors.Join(err, m.storage.Close(ctx))
+	err = errors.Join(err, m.api.management.Stop(ctx)) // probes are served until the very end
 
 	return err
 }
@@ -0,0 +1,19 @@
+package actor
+
+import "context"
+
+type contextKey struct{}
+
+// Actor of changes made by the microservice itself (e.g. purge of deleted data).
+const System = "system"
+
+// Actor performs changes of data on behalf of the request, e.g. "address:192.0.2.1" (recorded in history).
+func NewContext(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, contextKey{}, actor)
+}
+
+// Returns empty string if actor is unknown.
+func FromContext(ctx context.Context) string {
+	actor, _ := ctx.Value(contextKey{}).(string)
+	return actor
+}
@@ -0,0 +1,14 @@
+package actor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromContext(t *testing.T) {
+	require.Equal(t, "", FromContext(context.Background()))
+	require.Equal(t, System, FromContext(NewContext(context.Background(), System)))
+	require.Equal(t, "address:192.0.2.1", FromContext(NewContext(NewContext(context.Background(), System), "address:192.0.2.1")))
+}
@@ -0,0 +1,61 @@
+// Package countries is a registry of ISO 3166-1 alpha-2 country codes
+// with English and Russian country names and UN geoscheme regions.
+package countries
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"strings"
+)
+
+type Country struct {
+	Code   string // ISO 3166-1 alpha-2
+	NameEn string
+	NameRu string
+	Region string // Africa, Americas, Antarctica, Asia, Europe, Oceania
+}
+
+//go:embed countries.csv
+var registryData string
+
+var registry = mustLoad(registryData)
+
+// Lookup finds country by code, case-insensitively.
+func Lookup(code string) (*Country, bool) {
+	country, ok := registry[strings.ToUpper(strings.TrimSpace(code))]
+	return country, ok
+}
+
+// Normalize returns code in canonical (upper) case, if it's known.
+func Normalize(code string) (string, bool) {
+	country, ok := Lookup(code)
+
+	if !ok {
+		return "", false
+	}
+
+	return country.Code, true
+}
+
+func Valid(code string) bool {
+	_, ok := registry[code]
+	return ok
+}
+
+// Header: code, en, ru, region.
+func mustLoad(data string) map[string]*Country {
+	records, err := csv.NewReader(strings.NewReader(data)).ReadAll()
+
+	if err != nil {
+		panic(fmt.Sprintf("failed to load countries registry: %s", err))
+	}
+
+	result := make(map[string]*Country, len(records))
+
+	for _, r := range records[1:] {
+		result[r[0]] = &Country{Code: r[0], NameEn: r[1], NameRu: r[2], Region: r[3]}
+	}
+
+	return result
+}
@@ -0,0 +1,57 @@
+package countries
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		name   string
+		code   string
+		want   *Country
+		wantOk bool
+	}{
+		{
+			name:   "Upper case",
+			code:   "RU",
+			want:   &Country{Code: "RU", NameEn: "Russian Federation", NameRu: "Россия", Region: "Europe"},
+			wantOk: true,
+		},
+		{
+			name:   "Mixed case",
+			code:   " Kz",
+			want:   &Country{Code: "KZ", NameEn: "Kazakhstan", NameRu: "Казахстан", Region: "Asia"},
+			wantOk: true,
+		},
+		{
+			name: "Alpha-3 code",
+			code: "RUS",
+		},
+		{
+			name: "Unknown code",
+			code: "XX",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Lookup(tt.code)
+
+			require.Equal(t, tt.wantOk, ok)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	require.Len(t, registry, 249)
+
+	for code, country := range registry {
+		require.Len(t, code, 2)
+		require.NotEmpty(t, country.NameEn, code)
+		require.NotEmpty(t, country.NameRu, code)
+		require.NotEmpty(t, country.Region, code)
+	}
+}
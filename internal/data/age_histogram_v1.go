@@ -0,0 +1,87 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/barpav/demography/internal/rest/models"
+	"github.com/doug-martin/goqu/v9"
+)
+
+func (s *Storage) AgeHistogramV1(ctx context.Context, filters *models.SearchFilters, width int) (result *models.AgeHistogramV1, err error) {
+	age := personAge(filters.AsOf)
+	bucket := goqu.L("(? / ?) * ?", age, width, width)
+
+	builder := goqu.Select(
+		bucket,
+		goqu.Cast(personGender, "varchar"),
+		goqu.COUNT(goqu.Star()),
+	).From(peopleTable(filters)).
+		Where(age.IsNotNull()).
+		Where(personFilters(filters)...).
+		GroupBy(goqu.L("1"), goqu.L("2")).
+		Order(goqu.L("1").Asc())
+
+	var query string
+	query, _, err = builder.ToSQL()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query text for age histogram (v1): %w", err)
+	}
+
+	var rows *sql.Rows
+	rows, err = s.db.QueryContext(ctx, query)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute sql query for age histogram (v1): %w", err)
+	}
+
+	defer rows.Close()
+
+	result = &models.AgeHistogramV1{BucketWidth: width, Buckets: make([]*models.AgeBucketV1, 0)}
+	var last *models.AgeBucketV1
+
+	for rows.Next() {
+		var from int
+		var gender string
+		var count int64
+
+		err = rows.Scan(&from, &gender, &count)
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to process sql query result for age histogram (v1): %w", err)
+		}
+
+		// buckets are contiguous, even if some of them are empty
+		for last == nil || last.From < from {
+			next := from
+
+			if last != nil {
+				next = last.From + width
+			}
+
+			last = &models.AgeBucketV1{From: next, To: next + width - 1}
+			result.Buckets = append(result.Buckets, last)
+		}
+
+		switch gender {
+		case "male":
+			last.Male += count
+		case "female":
+			last.Female += count
+		default:
+			last.Unknown += count
+		}
+
+		result.Total += count
+	}
+
+	err = rows.Err()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to process sql query results for age histogram (v1): %w", err)
+	}
+
+	return result, nil
+}
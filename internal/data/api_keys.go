@@ -0,0 +1,157 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/barpav/demography/internal/rest/models"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const pgUniqueViolation = "23505"
+
+type queryGetAPIKey struct{}
+
+func (q queryGetAPIKey) text() string {
+	return `
+	SELECT key_name, scopes, created_at
+	FROM api_keys
+	WHERE key_hash = $1 AND revoked_at IS NULL;
+	`
+}
+
+type queryCreateAPIKey struct{}
+
+func (q queryCreateAPIKey) text() string {
+	return `
+	INSERT INTO api_keys (key_name, key_hash, scopes)
+	VALUES ($1, $2, $3)
+	RETURNING created_at;
+	`
+}
+
+type queryRevokeAPIKey struct{}
+
+func (q queryRevokeAPIKey) text() string {
+	return `
+	UPDATE api_keys SET revoked_at = now()
+	WHERE key_name = $1 AND revoked_at IS NULL;
+	`
+}
+
+type queryGetAPIKeys struct{}
+
+func (q queryGetAPIKeys) text() string {
+	return `
+	SELECT key_name, scopes, created_at, revoked_at
+	FROM api_keys
+	ORDER BY id;
+	`
+}
+
+type ErrAPIKeyNotFound struct{}
+
+type ErrAPIKeyExists struct{}
+
+// Returns nil, nil if key is not found or revoked.
+func (s *Storage) APIKey(ctx context.Context, keyHash []byte) (*models.APIKey, error) {
+	key := &models.APIKey{}
+	err := s.queries[queryGetAPIKey{}].QueryRowContext(ctx, keyHash).Scan(
+		&key.Name,
+		pgtype.NewMap().SQLScanner(&key.Scopes),
+		&key.CreatedAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("failed to receive API key: %w", err)
+	}
+
+	return key, nil
+}
+
+// Name of the key must be unique among active keys.
+func (s *Storage) CreateAPIKey(ctx context.Context, key *models.APIKey, keyHash []byte) error {
+	err := s.queries[queryCreateAPIKey{}].QueryRowContext(ctx, key.Name, keyHash, key.Scopes).Scan(&key.CreatedAt)
+
+	if err != nil {
+		var pgErr *pgconn.PgError
+
+		if errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation {
+			return ErrAPIKeyExists{}
+		}
+
+		return fmt.Errorf("failed to create API key: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Storage) RevokeAPIKey(ctx context.Context, name string) error {
+	result, err := s.queries[queryRevokeAPIKey{}].ExecContext(ctx, name)
+
+	var revoked int64
+	if err == nil {
+		revoked, err = result.RowsAffected()
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to revoke API key: %w", err)
+	}
+
+	if revoked == 0 {
+		return ErrAPIKeyNotFound{}
+	}
+
+	return nil
+}
+
+// All keys including revoked ones, in order of creation.
+func (s *Storage) APIKeys(ctx context.Context) (keys []*models.APIKey, err error) {
+	var rows *sql.Rows
+	rows, err = s.queries[queryGetAPIKeys{}].QueryContext(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute sql query for API keys: %w", err)
+	}
+
+	defer rows.Close()
+
+	types := pgtype.NewMap() // for arrays
+
+	for rows.Next() {
+		key := &models.APIKey{}
+		var revokedAt sql.NullTime
+		err = rows.Scan(&key.Name, types.SQLScanner(&key.Scopes), &key.CreatedAt, &revokedAt)
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to process sql query result for API keys: %w", err)
+		}
+
+		key.RevokedAt = revokedAt.Time
+
+		keys = append(keys, key)
+	}
+
+	err = rows.Err()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to process sql query results for API keys: %w", err)
+	}
+
+	return keys, nil
+}
+
+func (e ErrAPIKeyNotFound) Error() string {
+	return "API key not found"
+}
+
+func (e ErrAPIKeyExists) Error() string {
+	return "active API key with the same name already exists"
+}
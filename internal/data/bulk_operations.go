@@ -0,0 +1,130 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/barpav/demography/internal/rest/models"
+	"github.com/doug-martin/goqu/v9"
+)
+
+var patchColumns = map[string]string{
+	"surname":    "surname",
+	"name":       "person_name",
+	"patronymic": "patronymic",
+	"age":        "age",
+	"gender":     "gender",
+	"country":    "country",
+}
+
+func (s *Storage) CountPeople(ctx context.Context, filters *models.SearchFilters) (count int64, err error) {
+	query, _, err := goqu.Select(goqu.COUNT(goqu.Star())).From("people").Where(personFilters(filters)...).ToSQL()
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to build sql query text for people count: %w", err)
+	}
+
+	err = s.db.QueryRowContext(ctx, query).Scan(&count)
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to count people: %w", err)
+	}
+
+	return count, nil
+}
+
+// Soft deletion, people data can be restored until purged.
+// Returns ErrPeopleAffectedChanged if not the expected number of people is affected.
+func (s *Storage) DeletePeople(ctx context.Context, filters *models.SearchFilters, expected int64) (deleted int64, err error) {
+	query, _, err := goqu.Update("people").Set(goqu.Record{"deleted_at": goqu.L("now()")}).
+		Where(personFilters(filters)...).ToSQL()
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to build sql query text for people deletion: %w", err)
+	}
+
+	return s.execAffecting(ctx, query, expected, "delete people data")
+}
+
+// Returns ErrPeopleAffectedChanged if not the expected number of people is affected.
+func (s *Storage) PatchPeopleDataV1(ctx context.Context, filters *models.SearchFilters,
+	patch *models.PersonDataPatchV1, expected int64) (updated int64, err error) {
+	query, _, err := goqu.Update("people").Set(patchRecord(patch)).Where(personFilters(filters)...).ToSQL()
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to build sql query text for people data patch (v1): %w", err)
+	}
+
+	updated, err = s.execAffecting(ctx, query, expected, "patch people data (v1)")
+
+	if violation, ok := constraintViolation(err); ok {
+		return 0, violation
+	}
+
+	return updated, err
+}
+
+func patchRecord(patch *models.PersonDataPatchV1) goqu.Record {
+	record := goqu.Record{}
+
+	for field, value := range patch.Changes() {
+		if field == "gender" {
+			record["gender_source"] = nil
+
+			if value == nil {
+				value = goqu.Cast(goqu.V(models.GenderUnknown), "gender") // undetermined gender has no source
+			} else {
+				value = goqu.Cast(goqu.V(value), "gender")
+				record["gender_source"] = goqu.Cast(goqu.V(models.GenderSourceDeclared), "gender_source")
+			}
+		}
+
+		// birth date is kept only if it's consistent with the new age
+		if field == "age" {
+			record["birth_date"] = goqu.Case().
+				When(goqu.L("date_part('year', age(current_date, birth_date))").Eq(goqu.V(value)), goqu.C("birth_date"))
+		}
+
+		record[patchColumns[field]] = value
+	}
+
+	return record
+}
+
+type ErrPeopleAffectedChanged struct{}
+
+// Returns number of affected rows, changes are rolled back if it's not the expected one.
+func (s *Storage) execAffecting(ctx context.Context, query string, expected int64, operation string) (int64, error) {
+	var affected int64
+	err := s.changeOnBehalf(ctx, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, query)
+
+		if err == nil {
+			affected, err = result.RowsAffected()
+		}
+
+		if err == nil && affected != expected {
+			err = ErrPeopleAffectedChanged{}
+		}
+
+		return err
+	})
+
+	if _, ok := err.(ErrPeopleAffectedChanged); ok {
+		return 0, err
+	}
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to %s: %w", operation, err)
+	}
+
+	return affected, nil
+}
+
+func (e ErrPeopleAffectedChanged) Error() string {
+	return "number of affected people has changed"
+}
+
+func (e ErrPeopleAffectedChanged) ImplementsPeopleAffectedChangedError() {
+}
@@ -1,6 +1,10 @@
 package data
 
-import "os"
+import (
+	"os"
+	"strconv"
+	"time"
+)
 
 const (
 	defaultHost     = "localhost"
@@ -8,6 +12,9 @@ const (
 	defaultDatabase = "demography"
 	defaultUser     = "postgres"
 	defaultPassword = "postgres"
+
+	defaultDeletedRetentionDays = 30
+	defaultPurgeIntervalMinutes = 60
 )
 
 const (
@@ -16,6 +23,9 @@ const (
 	envVarDatabase = "DMG_STORAGE_DATABASE"
 	envVarUser     = "DMG_STORAGE_USER"
 	envVarPassword = "DMG_STORAGE_PASSWORD"
+
+	envVarDeletedRetentionDays = "DMG_DELETED_RETENTION_DAYS"
+	envVarPurgeIntervalMinutes = "DMG_PURGE_INTERVAL_MINUTES"
 )
 
 type config struct {
@@ -24,6 +34,9 @@ type config struct {
 	database string
 	user     string
 	password string
+
+	deletedRetention time.Duration // deleted people data can be restored during this period
+	purgeInterval    time.Duration // how often deleted people data is checked for expiration
 }
 
 func (c *config) Read() {
@@ -32,6 +45,22 @@ func (c *config) Read() {
 	readSetting(envVarDatabase, defaultDatabase, &c.database)
 	readSetting(envVarUser, defaultUser, &c.user)
 	readSetting(envVarPassword, defaultPassword, &c.password)
+
+	var days, minutes int
+	readNumericSetting(envVarDeletedRetentionDays, defaultDeletedRetentionDays, &days)
+
+	if days <= 0 {
+		days = defaultDeletedRetentionDays
+	}
+
+	readNumericSetting(envVarPurgeIntervalMinutes, defaultPurgeIntervalMinutes, &minutes)
+
+	if minutes <= 0 {
+		minutes = defaultPurgeIntervalMinutes
+	}
+
+	c.deletedRetention = time.Duration(days) * 24 * time.Hour
+	c.purgeInterval = time.Duration(minutes) * time.Minute
 }
 
 func readSetting(setting, defaultValue string, result *string) {
@@ -40,3 +69,18 @@ func readSetting(setting, defaultValue string, result *string) {
 		*result = defaultValue
 	}
 }
+
+func readNumericSetting(setting string, defaultValue int, result *int) {
+	val := os.Getenv(setting)
+
+	if val != "" {
+		valNum, err := strconv.Atoi(val)
+
+		if err == nil {
+			*result = valNum
+			return
+		}
+	}
+
+	*result = defaultValue
+}
@@ -0,0 +1,98 @@
+package data
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// PostgreSQL error codes (SQLSTATE) of person data violating database constraints.
+const (
+	pgStringDataRightTruncation = "22001"
+	pgNumericValueOutOfRange    = "22003"
+	pgInvalidTextRepresentation = "22P02"
+	pgNotNullViolation          = "23502"
+	pgCheckViolation            = "23514"
+)
+
+// Known check constraints of people table by schema field names.
+var checkConstraints = map[string]ErrPersonDataConstraintViolation{
+	"people_age_check": {Field: "age", Reason: "Person's age must be between 0 and 150."},
+}
+
+// Schema field names by people table columns.
+var schemaFields = map[string]string{
+	"surname":       "surname",
+	"person_name":   "name",
+	"patronymic":    "patronymic",
+	"middle_names":  "middleNames",
+	"birth_date":    "birthDate",
+	"age":           "age",
+	"gender":        "gender",
+	"gender_source": "genderSource",
+	"country":       "country",
+	"nationalities": "nationalities",
+	"tags":          "tags",
+}
+
+type ErrPersonDataConstraintViolation struct {
+	Field  string // schema field name, empty if unknown
+	Reason string
+}
+
+// Recognizes database errors caused by person data itself (not by storage failures).
+func constraintViolation(err error) (violation ErrPersonDataConstraintViolation, ok bool) {
+	var pgErr *pgconn.PgError
+
+	if !errors.As(err, &pgErr) {
+		return violation, false
+	}
+
+	violation.Field = schemaFields[pgErr.ColumnName]
+
+	switch pgErr.Code {
+	case pgCheckViolation:
+		if known, found := checkConstraints[pgErr.ConstraintName]; found {
+			return known, true
+		}
+
+		violation.Reason = "Value does not satisfy data constraints."
+	case pgNotNullViolation:
+		violation.Reason = "Value must be specified."
+	case pgStringDataRightTruncation:
+		violation.Reason = "Value is too long."
+	case pgNumericValueOutOfRange:
+		violation.Reason = "Numeric value is out of range."
+	case pgInvalidTextRepresentation:
+		violation.Reason = "Value has incorrect format."
+	default:
+		return violation, false
+	}
+
+	return violation, true
+}
+
+// Violation of data constraints by an item of people data (others are not saved either).
+type ErrPeopleDataConstraintViolation struct {
+	Item int // index of the violating item
+	ErrPersonDataConstraintViolation
+}
+
+func (e ErrPeopleDataConstraintViolation) ViolatingItem() int {
+	return e.Item
+}
+
+func (e ErrPersonDataConstraintViolation) Error() string {
+	if e.Field == "" {
+		return "person data constraint violation: " + e.Reason
+	}
+
+	return "person data constraint violation (" + e.Field + "): " + e.Reason
+}
+
+func (e ErrPersonDataConstraintViolation) ImplementsPersonDataConstraintViolationError() {
+}
+
+func (e ErrPersonDataConstraintViolation) InvalidField() (name, reason string) {
+	return e.Field, e.Reason
+}
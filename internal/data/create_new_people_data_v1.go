@@ -0,0 +1,46 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/barpav/demography/internal/rest/models"
+)
+
+// All or nothing: people data is created in a single transaction.
+func (s *Storage) CreateNewPeopleDataV1(ctx context.Context, data []*models.EnrichedPersonDataV1) error {
+	ids, versions := make([]int64, len(data)), make([]int, len(data))
+
+	err := s.changeOnBehalf(ctx, func(tx *sql.Tx) error {
+		stmt := tx.StmtContext(ctx, s.queries[queryCreateNewPersonDataV1{}])
+
+		for i, d := range data {
+			err := stmt.QueryRowContext(ctx, d.Surname, d.Name, d.Patronymic, d.Age, d.Gender, d.Country).Scan(&ids[i], &versions[i])
+
+			if violation, ok := constraintViolation(err); ok {
+				return ErrPeopleDataConstraintViolation{Item: i, ErrPersonDataConstraintViolation: violation}
+			}
+
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if violation, ok := err.(ErrPeopleDataConstraintViolation); ok {
+		return violation
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to create new people data (v1): %w", err)
+	}
+
+	for i := range data {
+		data[i].Id, data[i].Version = ids[i], versions[i]
+	}
+
+	return nil
+}
@@ -2,6 +2,7 @@ package data
 
 import (
 	"context"
+	"database/sql"
 
 	"github.com/barpav/demography/internal/rest/models"
 )
@@ -10,14 +11,23 @@ type queryCreateNewPersonDataV1 struct{}
 
 func (q queryCreateNewPersonDataV1) text() string {
 	return `
-	INSERT INTO people (surname, person_name, patronymic, age, gender, country)
-	VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, 0), NULLIF($5, '')::gender, NULLIF($6, ''))
-	RETURNING id;
+	INSERT INTO people (surname, person_name, patronymic, age, gender, country, gender_source)
+	VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, 0), COALESCE(NULLIF($5, '')::gender, 'unknown'), NULLIF($6, ''),
+		CASE WHEN $5 <> '' THEN 'statistics'::gender_source END) -- undetermined gender has no source
+	RETURNING id, version;
 	`
 }
 
 func (s *Storage) CreateNewPersonDataV1(ctx context.Context, data *models.EnrichedPersonDataV1) error {
-	row := s.queries[queryCreateNewPersonDataV1{}].QueryRowContext(ctx,
-		data.Surname, data.Name, data.Patronymic, data.Age, data.Gender, data.Country)
-	return row.Scan(&data.Id)
+	err := s.changeOnBehalf(ctx, func(tx *sql.Tx) error {
+		row := tx.StmtContext(ctx, s.queries[queryCreateNewPersonDataV1{}]).QueryRowContext(ctx,
+			data.Surname, data.Name, data.Patronymic, data.Age, data.Gender, data.Country)
+		return row.Scan(&data.Id, &data.Version)
+	})
+
+	if violation, ok := constraintViolation(err); ok {
+		return violation
+	}
+
+	return err
 }
@@ -0,0 +1,36 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/barpav/demography/internal/rest/models"
+)
+
+type queryCreateNewPersonDataV2 struct{}
+
+func (q queryCreateNewPersonDataV2) text() string {
+	return `
+	INSERT INTO people (surname, person_name, patronymic, age, gender, country,
+		middle_names, birth_date, nationalities, tags, gender_source)
+	VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, 0), NULLIF($5, '')::gender, NULLIF($6, ''),
+		COALESCE($7::varchar[], '{}'), NULLIF($8, '')::date, COALESCE($9::varchar[], '{}'), COALESCE($10::varchar[], '{}'),
+		NULLIF($11, '')::gender_source)
+	RETURNING id, version;
+	`
+}
+
+func (s *Storage) CreateNewPersonDataV2(ctx context.Context, data *models.EnrichedPersonDataV2) error {
+	err := s.changeOnBehalf(ctx, func(tx *sql.Tx) error {
+		row := tx.StmtContext(ctx, s.queries[queryCreateNewPersonDataV2{}]).QueryRowContext(ctx,
+			data.Surname, data.Name, data.Patronymic, data.Age, data.Gender, data.Country,
+			data.MiddleNames, data.BirthDate, data.Nationalities, data.Tags, data.GenderSource)
+		return row.Scan(&data.Id, &data.Version)
+	})
+
+	if violation, ok := constraintViolation(err); ok {
+		return violation
+	}
+
+	return err
+}
@@ -2,6 +2,7 @@ package data
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 )
 
@@ -9,24 +10,31 @@ type queryDeletePersonData struct{}
 
 func (q queryDeletePersonData) text() string {
 	return `
-	DELETE FROM people WHERE id = $1;
+	UPDATE people SET deleted_at = now()
+	WHERE id = $1 AND deleted_at IS NULL AND ($2::integer[] IS NULL OR version = ANY($2::integer[]));
 	`
 }
 
-func (s *Storage) DeletePersonData(ctx context.Context, id int64) error {
-	result, err := s.queries[queryDeletePersonData{}].ExecContext(ctx, id)
-
+// Soft deletion, person data can be restored until purged. Person data is deleted only if its version
+// is one of expected (no versions means unconditional deletion).
+func (s *Storage) DeletePersonData(ctx context.Context, id int64, versions []int) error {
 	var deleted int64
-	if err == nil {
-		deleted, err = result.RowsAffected()
-	}
+	err := s.changeOnBehalf(ctx, func(tx *sql.Tx) error {
+		result, err := tx.StmtContext(ctx, s.queries[queryDeletePersonData{}]).ExecContext(ctx, id, versionsArg(versions))
+
+		if err == nil {
+			deleted, err = result.RowsAffected()
+		}
+
+		return err
+	})
 
 	if err != nil {
 		return fmt.Errorf("failed to delete person data: %w", err)
 	}
 
 	if deleted == 0 {
-		return ErrPersonDataNotFound{}
+		return s.personDataNotChanged(ctx, id, versions)
 	}
 
 	return nil
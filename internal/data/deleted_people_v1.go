@@ -0,0 +1,78 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/barpav/demography/internal/rest/models"
+	"github.com/doug-martin/goqu/v9"
+)
+
+// Deleted (not yet purged) people data, paged by id.
+func (s *Storage) DeletedPeopleV1(ctx context.Context, after int64, limit int) (result *models.DeletedPeopleV1, err error) {
+	builder := goqu.Select(
+		"id",
+		"surname",
+		"person_name",
+		goqu.COALESCE(goqu.C("patronymic"), ""),
+		goqu.COALESCE(personAge(time.Time{}), 0),
+		genderV1,
+		goqu.COALESCE(goqu.C("country"), ""),
+		"deleted_at",
+	).From("people").
+		Where(goqu.C("deleted_at").IsNotNull(), goqu.C("id").Gt(after)).
+		Order(goqu.C("id").Asc()).
+		Limit(uint(limit))
+
+	var query string
+	query, _, err = builder.ToSQL()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query text for deleted people (v1): %w", err)
+	}
+
+	var rows *sql.Rows
+	rows, err = s.db.QueryContext(ctx, query)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute sql query for deleted people (v1): %w", err)
+	}
+
+	defer rows.Close()
+
+	result = &models.DeletedPeopleV1{Data: make([]*models.DeletedPersonDataV1, 0, limit)}
+
+	for rows.Next() {
+		info := &models.DeletedPersonDataV1{}
+		err = rows.Scan(
+			&info.Id,
+			&info.Surname,
+			&info.Name,
+			&info.Patronymic,
+			&info.Age,
+			&info.Gender,
+			&info.Country,
+			&info.DeletedAt,
+		)
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to process sql query result for deleted people (v1): %w", err)
+		}
+
+		info.DeletedAt = info.DeletedAt.UTC()
+		info.PurgeAt = info.DeletedAt.Add(s.cfg.deletedRetention)
+		result.Data = append(result.Data, info)
+	}
+
+	err = rows.Err()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to process sql query results for deleted people (v1): %w", err)
+	}
+
+	result.Total = len(result.Data)
+
+	return result, nil
+}
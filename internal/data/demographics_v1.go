@@ -0,0 +1,117 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/barpav/demography/internal/rest/models"
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+)
+
+func (s *Storage) DemographicsV1(ctx context.Context, filters *models.SearchFilters,
+	grouping *models.DemographicsGrouping) (result *models.DemographicsV1, err error) {
+	var gender, country interface{} = goqu.V(""), goqu.V("")
+	groupBy := make([]interface{}, 0, 2)
+
+	if grouping.Gender {
+		gender = genderV1
+		groupBy = append(groupBy, gender)
+	}
+
+	if grouping.Country {
+		country = goqu.COALESCE(goqu.C("country"), "")
+		groupBy = append(groupBy, country)
+	}
+
+	age := personAge(filters.AsOf)
+	builder := goqu.Select(
+		gender,
+		country,
+		goqu.COUNT(goqu.Star()),
+		goqu.COUNT(age),
+		goqu.MIN(age),
+		goqu.MAX(age),
+		goqu.L("avg(?)::float8", age),
+		percentile(0.5, age),
+		percentile(0.1, age),
+		percentile(0.25, age),
+		percentile(0.75, age),
+		percentile(0.9, age),
+	).From(peopleTable(filters)).Where(personFilters(filters)...)
+
+	if len(groupBy) > 0 {
+		builder = builder.GroupBy(groupBy...).Order(goqu.COUNT(goqu.Star()).Desc(), goqu.L("1").Asc(), goqu.L("2").Asc())
+	}
+
+	var query string
+	query, _, err = builder.ToSQL()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query text for demographics (v1): %w", err)
+	}
+
+	var rows *sql.Rows
+	rows, err = s.db.QueryContext(ctx, query)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute sql query for demographics (v1): %w", err)
+	}
+
+	defer rows.Close()
+
+	result = &models.DemographicsV1{Groups: make([]*models.DemographicsGroupV1, 0)}
+
+	for rows.Next() {
+		group := &models.DemographicsGroupV1{}
+		age := &models.AgeStatisticsV1{}
+		var min, max sql.NullInt64
+		var mean, median, p10, p25, p75, p90 sql.NullFloat64
+
+		err = rows.Scan(
+			&group.Gender,
+			&group.Country,
+			&group.Count,
+			&age.Count,
+			&min,
+			&max,
+			&mean,
+			&median,
+			&p10,
+			&p25,
+			&p75,
+			&p90,
+		)
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to process sql query result for demographics (v1): %w", err)
+		}
+
+		if group.Count == 0 {
+			continue // no grouping and nothing found
+		}
+
+		if age.Count > 0 {
+			age.Min, age.Max = int(min.Int64), int(max.Int64)
+			age.Mean, age.Median = mean.Float64, median.Float64
+			age.P10, age.P25, age.P75, age.P90 = p10.Float64, p25.Float64, p75.Float64, p90.Float64
+			group.Age = age
+		}
+
+		result.Total += group.Count
+		result.Groups = append(result.Groups, group)
+	}
+
+	err = rows.Err()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to process sql query results for demographics (v1): %w", err)
+	}
+
+	return result, nil
+}
+
+func percentile(fraction float64, age exp.Expression) exp.LiteralExpression {
+	return goqu.L(fmt.Sprintf("percentile_cont(%g) WITHIN GROUP (ORDER BY ?)", fraction), age)
+}
@@ -11,22 +11,31 @@ import (
 type queryGetEnrichedPersonDataV1 struct{}
 
 func (q queryGetEnrichedPersonDataV1) text() string {
+	return enrichedPersonDataV1Query("people", "current_date")
+}
+
+// Person data is selected from the source by id ($1), age is calculated at the moment.
+func enrichedPersonDataV1Query(source, moment string) string {
 	return `
 	SELECT
 		surname,
 		person_name,
 		COALESCE(patronymic, ''),
-		COALESCE(age, 0),
-		COALESCE(gender::varchar, ''),
-		COALESCE(country, '')
-	FROM people
-	WHERE id = $1;
+		COALESCE(` + personAgeColumn(moment) + `, 0),
+		` + genderV1Column + `,
+		COALESCE(country, ''),
+		version
+	FROM ` + source + `
+	WHERE id = $1 AND deleted_at IS NULL;
 	`
 }
 
 // Returns nil, nil if data is not found.
 func (s *Storage) EnrichedPersonDataV1(ctx context.Context, id int64) (*models.EnrichedPersonDataV1, error) {
-	row := s.queries[queryGetEnrichedPersonDataV1{}].QueryRowContext(ctx, id)
+	return scanEnrichedPersonDataV1(id, s.queries[queryGetEnrichedPersonDataV1{}].QueryRowContext(ctx, id))
+}
+
+func scanEnrichedPersonDataV1(id int64, row *sql.Row) (*models.EnrichedPersonDataV1, error) {
 	err := row.Err()
 
 	if err != nil {
@@ -41,6 +50,7 @@ func (s *Storage) EnrichedPersonDataV1(ctx context.Context, id int64) (*models.E
 		&data.Age,
 		&data.Gender,
 		&data.Country,
+		&data.Version,
 	)
 
 	if err != nil {
@@ -0,0 +1,77 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/barpav/demography/internal/rest/models"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type queryGetEnrichedPersonDataV2 struct{}
+
+func (q queryGetEnrichedPersonDataV2) text() string {
+	return enrichedPersonDataV2Query("people", "current_date")
+}
+
+// Person data is selected from the source by id ($1), age is calculated at the moment.
+func enrichedPersonDataV2Query(source, moment string) string {
+	return `
+	SELECT
+		surname,
+		person_name,
+		COALESCE(patronymic, ''),
+		middle_names,
+		COALESCE(to_char(birth_date, 'YYYY-MM-DD'), ''),
+		COALESCE(` + personAgeColumn(moment) + `, 0),
+		` + genderColumn + `::varchar,
+		COALESCE(gender_source::varchar, ''),
+		COALESCE(country, ''),
+		nationalities,
+		tags,
+		version
+	FROM ` + source + `
+	WHERE id = $1 AND deleted_at IS NULL;
+	`
+}
+
+// Returns nil, nil if data is not found.
+func (s *Storage) EnrichedPersonDataV2(ctx context.Context, id int64) (*models.EnrichedPersonDataV2, error) {
+	return scanEnrichedPersonDataV2(id, s.queries[queryGetEnrichedPersonDataV2{}].QueryRowContext(ctx, id))
+}
+
+func scanEnrichedPersonDataV2(id int64, row *sql.Row) (*models.EnrichedPersonDataV2, error) {
+	err := row.Err()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute sql statement (enrichedPersonData.v2): %w", err)
+	}
+
+	types := pgtype.NewMap() // for arrays
+	data := &models.EnrichedPersonDataV2{Id: id}
+	err = row.Scan(
+		&data.Surname,
+		&data.Name,
+		&data.Patronymic,
+		types.SQLScanner(&data.MiddleNames),
+		&data.BirthDate,
+		&data.Age,
+		&data.Gender,
+		&data.GenderSource,
+		&data.Country,
+		types.SQLScanner(&data.Nationalities),
+		types.SQLScanner(&data.Tags),
+		&data.Version,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("failed to scan sql result (enrichedPersonData.v2): %w", err)
+	}
+
+	return data, nil
+}
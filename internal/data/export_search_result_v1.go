@@ -0,0 +1,79 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/barpav/demography/internal/rest/models"
+)
+
+const exportBatchSize = 1000
+
+// Full (unpaged) search result is read in batches via server-side cursor
+// and passed to the receiver one by one, so it's never buffered entirely.
+func (s *Storage) ExportSearchResultV1(ctx context.Context, filters *models.SearchFilters,
+	receive func(*models.EnrichedPersonDataV1) error) (err error) {
+	var query string
+	query, _, err = searchQueryV1(filters).ToSQL()
+
+	if err != nil {
+		return fmt.Errorf("failed to build sql query text for search result export (v1): %w", err)
+	}
+
+	var tx *sql.Tx
+	tx, err = s.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction for search result export (v1): %w", err)
+	}
+
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, "DECLARE export_search_result_v1 NO SCROLL CURSOR FOR "+query)
+
+	if err != nil {
+		return fmt.Errorf("failed to declare cursor for search result export (v1): %w", err)
+	}
+
+	fetch := fmt.Sprintf("FETCH FORWARD %d FROM export_search_result_v1", exportBatchSize)
+
+	for fetched := exportBatchSize; fetched == exportBatchSize; {
+		fetched, err = fetchSearchResultV1(ctx, tx, fetch, receive)
+
+		if err != nil {
+			return fmt.Errorf("failed to export search result (v1): %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func fetchSearchResultV1(ctx context.Context, tx *sql.Tx, fetch string,
+	receive func(*models.EnrichedPersonDataV1) error) (fetched int, err error) {
+	var rows *sql.Rows
+	rows, err = tx.QueryContext(ctx, fetch)
+
+	if err != nil {
+		return 0, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var info *models.EnrichedPersonDataV1
+		info, err = scanSearchResultV1(rows)
+
+		if err == nil {
+			err = receive(info)
+		}
+
+		if err != nil {
+			return fetched, err
+		}
+
+		fetched++
+	}
+
+	return fetched, rows.Err()
+}
@@ -0,0 +1,13 @@
+package data
+
+import "github.com/doug-martin/goqu/v9"
+
+// Schemas of version 1 support only male and female genders, others are not shown.
+const genderV1Column = `CASE WHEN gender IN ('male', 'female') THEN gender::varchar ELSE '' END`
+
+var genderV1 = goqu.L(genderV1Column)
+
+// Undetermined gender is unknown (without source), but snapshots in history made before it was backfilled can contain NULL.
+const genderColumn = `COALESCE(gender, 'unknown')`
+
+var personGender = goqu.L(genderColumn)
@@ -0,0 +1,171 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/barpav/demography/internal/rest/models"
+)
+
+type queryPurgeExpiredIdempotencyKeys struct{}
+
+func (q queryPurgeExpiredIdempotencyKeys) text() string {
+	return `
+	DELETE FROM idempotency_keys WHERE expires_at < now();
+	`
+}
+
+type queryReserveIdempotencyKey struct{}
+
+func (q queryReserveIdempotencyKey) text() string {
+	return `
+	INSERT INTO idempotency_keys (client, idempotency_key, reservation, fingerprint, locked_until, expires_at)
+	VALUES ($1, $2, $3, $4, now() + $5 * interval '1 second', now() + $6 * interval '1 second')
+	ON CONFLICT (client, idempotency_key) DO UPDATE SET -- reservation of request that never completed
+		reservation = EXCLUDED.reservation,
+		fingerprint = EXCLUDED.fingerprint,
+		locked_until = EXCLUDED.locked_until,
+		expires_at = EXCLUDED.expires_at
+	WHERE idempotency_keys.status_code IS NULL AND idempotency_keys.locked_until < now();
+	`
+}
+
+type queryGetIdempotentResponse struct{}
+
+func (q queryGetIdempotentResponse) text() string {
+	return `
+	SELECT fingerprint, status_code IS NOT NULL, COALESCE(status_code, 0), headers, body
+	FROM idempotency_keys
+	WHERE client = $1 AND idempotency_key = $2;
+	`
+}
+
+type querySaveIdempotentResponse struct{}
+
+func (q querySaveIdempotentResponse) text() string {
+	return `
+	UPDATE idempotency_keys SET
+		status_code = $3,
+		headers = $4,
+		body = $5,
+		locked_until = NULL,
+		reservation = NULL
+	WHERE client = $1 AND idempotency_key = $2 AND reservation = $6;
+	`
+}
+
+type queryReleaseIdempotencyKey struct{}
+
+func (q queryReleaseIdempotencyKey) text() string {
+	return `
+	DELETE FROM idempotency_keys WHERE client = $1 AND idempotency_key = $2 AND reservation = $3;
+	`
+}
+
+// Attempts to reserve the key which is released or expired while its response is being received.
+const idempotencyKeyReservationAttempts = 3
+
+type ErrIdempotencyKeyReservationLost struct{}
+
+// Keys of different clients never clash. Returns nil if key is reserved for the new request, otherwise response
+// (probably not completed yet) of the request that reserved the key earlier. Reservation of request
+// that is not completed during lock period (e.g. service crashed) is taken over by the new request.
+func (s *Storage) ReserveIdempotencyKey(ctx context.Context, client, key string, reservation, fingerprint []byte,
+	lock, ttl time.Duration) (*models.IdempotentResponse, error) {
+	_, err := s.queries[queryPurgeExpiredIdempotencyKeys{}].ExecContext(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to purge expired idempotency keys: %w", err)
+	}
+
+	for attempt := 0; attempt < idempotencyKeyReservationAttempts; attempt++ {
+		var result sql.Result
+		result, err = s.queries[queryReserveIdempotencyKey{}].ExecContext(ctx,
+			client, key, reservation, fingerprint, int64(lock.Seconds()), int64(ttl.Seconds()))
+
+		var reserved int64
+		if err == nil {
+			reserved, err = result.RowsAffected()
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to reserve idempotency key: %w", err)
+		}
+
+		if reserved != 0 {
+			return nil, nil
+		}
+
+		response := &models.IdempotentResponse{}
+		var headers []byte
+		err = s.queries[queryGetIdempotentResponse{}].QueryRowContext(ctx, client, key).Scan(
+			&response.Fingerprint,
+			&response.Completed,
+			&response.StatusCode,
+			&headers,
+			&response.Body,
+		)
+
+		if err == sql.ErrNoRows { // released or expired in the meantime
+			continue
+		}
+
+		if err == nil && headers != nil {
+			err = json.Unmarshal(headers, &response.Header)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to receive idempotent response: %w", err)
+		}
+
+		return response, nil
+	}
+
+	return nil, fmt.Errorf("failed to reserve idempotency key in %d attempts", idempotencyKeyReservationAttempts)
+}
+
+// Returns ErrIdempotencyKeyReservationLost if reservation has been taken over by another request.
+func (s *Storage) SaveIdempotentResponse(ctx context.Context, client, key string, reservation []byte,
+	response *models.IdempotentResponse) error {
+	headers, err := json.Marshal(response.Header)
+
+	var result sql.Result
+	if err == nil {
+		result, err = s.queries[querySaveIdempotentResponse{}].ExecContext(ctx,
+			client, key, response.StatusCode, headers, response.Body, reservation)
+	}
+
+	var saved int64
+	if err == nil {
+		saved, err = result.RowsAffected()
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to save idempotent response: %w", err)
+	}
+
+	if saved == 0 {
+		return ErrIdempotencyKeyReservationLost{}
+	}
+
+	return nil
+}
+
+// Makes key available for retries if original request has failed.
+// Reservation taken over by another request is kept.
+func (s *Storage) ReleaseIdempotencyKey(ctx context.Context, client, key string, reservation []byte) error {
+	_, err := s.queries[queryReleaseIdempotencyKey{}].ExecContext(ctx, client, key, reservation)
+
+	if err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+
+	return nil
+}
+
+func (e ErrIdempotencyKeyReservationLost) Error() string {
+	return "idempotency key reservation has been taken over by another request"
+}
@@ -0,0 +1,143 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/barpav/demography/internal/actor"
+	"github.com/barpav/demography/internal/rest/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+// People data is inserted via COPY in a single transaction. Ids are reserved in advance
+// since COPY does not return generated values.
+func (s *Storage) ImportPeopleDataV1(ctx context.Context, data []*models.EnrichedPersonDataV1) error {
+	if len(data) == 0 {
+		return nil
+	}
+
+	conn, err := s.db.Conn(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to import people data (v1): %w", err)
+	}
+
+	defer conn.Close()
+
+	var ids []int64
+	err = conn.Raw(func(driverConn any) (err error) {
+		ids, err = copyPeopleDataV1(ctx, driverConn.(*stdlib.Conn).Conn(), data)
+		return err
+	})
+
+	if violation, ok := constraintViolation(err); ok {
+		return violation
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to import people data (v1): %w", err)
+	}
+
+	for i := range data {
+		data[i].Id = ids[i]
+	}
+
+	return nil
+}
+
+func copyPeopleDataV1(ctx context.Context, conn *pgx.Conn, data []*models.EnrichedPersonDataV1) (ids []int64, err error) {
+	err = errors.Join(registerType(ctx, conn, "gender"), registerType(ctx, conn, "gender_source"))
+
+	if err != nil {
+		return nil, err
+	}
+
+	var tx pgx.Tx
+	tx, err = conn.Begin(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, querySetActor{}.text(), actor.FromContext(ctx))
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to set actor of changes: %w", err)
+	}
+
+	var rows pgx.Rows
+	rows, err = tx.Query(ctx, "SELECT nextval('people_id_seq') FROM generate_series(1, $1);", len(data))
+
+	if err == nil {
+		ids, err = pgx.CollectRows(rows, pgx.RowTo[int64])
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to reserve ids: %w", err)
+	}
+
+	source := make([][]any, 0, len(data))
+
+	for i, d := range data {
+		gender, genderSource := d.Gender, any(models.GenderSourceStatistics)
+
+		if gender == "" {
+			gender, genderSource = models.GenderUnknown, nil // undetermined gender has no source
+		}
+
+		source = append(source, []any{
+			ids[i],
+			d.Surname,
+			d.Name,
+			nullIfEmpty(d.Patronymic),
+			nullIfEmpty(d.Age),
+			gender,
+			nullIfEmpty(d.Country),
+			genderSource,
+		})
+	}
+
+	_, err = tx.CopyFrom(ctx,
+		pgx.Identifier{"people"},
+		[]string{"id", "surname", "person_name", "patronymic", "age", "gender", "country", "gender_source"},
+		pgx.CopyFromRows(source),
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy data: %w", err)
+	}
+
+	return ids, tx.Commit(ctx)
+}
+
+// Binary COPY protocol requires custom types (e.g. enums) to be known by the connection.
+func registerType(ctx context.Context, conn *pgx.Conn, name string) error {
+	if _, ok := conn.TypeMap().TypeForName(name); ok {
+		return nil
+	}
+
+	t, err := conn.LoadType(ctx, name)
+
+	if err != nil {
+		return fmt.Errorf("failed to load type '%s': %w", name, err)
+	}
+
+	conn.TypeMap().RegisterType(t)
+
+	return nil
+}
+
+// Same as NULLIF for empty strings and zero numbers in sql.
+func nullIfEmpty[T comparable](value T) any {
+	var empty T
+
+	if value == empty {
+		return nil
+	}
+
+	return value
+}
@@ -0,0 +1,97 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/barpav/demography/internal/rest/models"
+	"github.com/doug-martin/goqu/v9"
+)
+
+var nameFieldColumns = map[models.NameField]string{
+	models.NameFieldSurname:    "surname",
+	models.NameFieldName:       "person_name",
+	models.NameFieldPatronymic: "patronymic",
+}
+
+func (s *Storage) NamePopularityV1(ctx context.Context, field models.NameField, filters *models.SearchFilters,
+	limit int) (result *models.NamePopularityV1, err error) {
+	column, ok := nameFieldColumns[field]
+
+	if !ok {
+		return nil, fmt.Errorf("failed to receive name popularity (v1): unknown field '%s'", field)
+	}
+
+	builder := goqu.Select(
+		goqu.C(column),
+		goqu.COUNT(goqu.Star()),
+		goqu.L("sum(count(*)) OVER ()::bigint"),
+	).From(peopleTable(filters)).
+		Where(goqu.C(column).IsNotNull()).
+		Where(personFilters(filters)...).
+		GroupBy(goqu.C(column)).
+		Order(goqu.COUNT(goqu.Star()).Desc(), goqu.C(column).Asc()).
+		Limit(uint(limit))
+
+	var query string
+	query, _, err = builder.ToSQL()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query text for name popularity (v1): %w", err)
+	}
+
+	var rows *sql.Rows
+	rows, err = s.db.QueryContext(ctx, query)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute sql query for name popularity (v1): %w", err)
+	}
+
+	defer rows.Close()
+
+	result = &models.NamePopularityV1{Data: make([]*models.NameRankV1, 0, limit)}
+
+	for rows.Next() {
+		rank := &models.NameRankV1{}
+		err = rows.Scan(&rank.Value, &rank.Count, &result.Total)
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to process sql query result for name popularity (v1): %w", err)
+		}
+
+		rank.Share = float64(rank.Count) * 100 / float64(result.Total)
+		result.Data = append(result.Data, rank)
+	}
+
+	err = rows.Err()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to process sql query results for name popularity (v1): %w", err)
+	}
+
+	return result, nil
+}
+
+func (s *Storage) NameSummaryV1(ctx context.Context, filters *models.SearchFilters) (result *models.NameSummaryV1, err error) {
+	builder := goqu.Select(
+		goqu.COUNT(goqu.Star()),
+		goqu.L("COALESCE(avg(?)::float8, 0)", personAge(filters.AsOf)),
+	).From(peopleTable(filters)).Where(personFilters(filters)...)
+
+	var query string
+	query, _, err = builder.ToSQL()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query text for name summary (v1): %w", err)
+	}
+
+	result = &models.NameSummaryV1{Name: filters.Name}
+	err = s.db.QueryRowContext(ctx, query).Scan(&result.Count, &result.AverageAge)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to receive name summary (v1): %w", err)
+	}
+
+	return result, nil
+}
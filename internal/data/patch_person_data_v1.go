@@ -0,0 +1,46 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/barpav/demography/internal/rest/models"
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+)
+
+// Only fields present in patch are updated. Person data is patched only if its version is one of expected
+// (no versions means unconditional patch). Returns new version of person data.
+func (s *Storage) PatchPersonDataV1(ctx context.Context, id int64, patch *models.PersonDataPatchV1, versions []int) (int, error) {
+	conditions := []exp.Expression{goqu.C("id").Eq(id), goqu.C("deleted_at").IsNull()}
+
+	if len(versions) != 0 {
+		conditions = append(conditions, goqu.C("version").In(versions))
+	}
+
+	query, _, err := goqu.Update("people").Set(patchRecord(patch)).Where(conditions...).Returning("version").ToSQL()
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to build sql query text for person data patch (v1): %w", err)
+	}
+
+	var newVersion int
+	err = s.changeOnBehalf(ctx, func(tx *sql.Tx) error {
+		return tx.QueryRowContext(ctx, query).Scan(&newVersion)
+	})
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, s.personDataNotChanged(ctx, id, versions)
+		}
+
+		if violation, ok := constraintViolation(err); ok {
+			return 0, violation
+		}
+
+		return 0, fmt.Errorf("failed to patch person data (v1): %w", err)
+	}
+
+	return newVersion, nil
+}
@@ -0,0 +1,89 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/barpav/demography/internal/rest/models"
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+)
+
+// Person data ($1) restored from the latest snapshot in history before the moment ($2).
+const personDataAsOf = `(
+		SELECT (jsonb_populate_record(NULL::people, after)).*
+		FROM people_history
+		WHERE person_id = $1 AND changed_at <= $2
+		ORDER BY id DESC
+		LIMIT 1
+	) AS people`
+
+type queryGetEnrichedPersonDataV1AsOf struct{}
+
+func (q queryGetEnrichedPersonDataV1AsOf) text() string {
+	return enrichedPersonDataV1Query(personDataAsOf, "$2::timestamptz")
+}
+
+type queryGetEnrichedPersonDataV2AsOf struct{}
+
+func (q queryGetEnrichedPersonDataV2AsOf) text() string {
+	return enrichedPersonDataV2Query(personDataAsOf, "$2::timestamptz")
+}
+
+type queryHistoryStart struct{}
+
+func (q queryHistoryStart) text() string {
+	return `
+	SELECT min(changed_at) FROM people_history;
+	`
+}
+
+// Earliest moment people data can be restored at, zero if history is empty.
+// History of data existed before it was introduced starts from the migration.
+func (s *Storage) HistoryStart(ctx context.Context) (time.Time, error) {
+	var start sql.NullTime
+	err := s.queries[queryHistoryStart{}].QueryRowContext(ctx).Scan(&start)
+
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to receive start of history: %w", err)
+	}
+
+	return start.Time, nil
+}
+
+// Returns nil, nil if data did not exist (or was deleted) at the moment.
+func (s *Storage) EnrichedPersonDataV1AsOf(ctx context.Context, id int64, asOf time.Time) (*models.EnrichedPersonDataV1, error) {
+	return scanEnrichedPersonDataV1(id, s.queries[queryGetEnrichedPersonDataV1AsOf{}].QueryRowContext(ctx, id, asOf))
+}
+
+// Returns nil, nil if data did not exist (or was deleted) at the moment.
+func (s *Storage) EnrichedPersonDataV2AsOf(ctx context.Context, id int64, asOf time.Time) (*models.EnrichedPersonDataV2, error) {
+	return scanEnrichedPersonDataV2(id, s.queries[queryGetEnrichedPersonDataV2AsOf{}].QueryRowContext(ctx, id, asOf))
+}
+
+// People table at the moment (restored from history), current one if the moment is not specified.
+// Purged people data is not restored, deleted one only if requested.
+func peopleTable(filters *models.SearchFilters) exp.Expression {
+	if filters.AsOf.IsZero() {
+		return goqu.T("people")
+	}
+
+	existing := "AND person_id IN (SELECT id FROM people WHERE deleted_at IS NULL)"
+
+	if filters.WithDeleted {
+		existing = ""
+	}
+
+	return goqu.L(`(
+		SELECT (jsonb_populate_record(NULL::people, after)).*
+		FROM (
+			SELECT DISTINCT ON (person_id) after
+			FROM people_history
+			WHERE changed_at <= ? `+existing+`
+			ORDER BY person_id, id DESC
+		) AS snapshots
+		WHERE after IS NOT NULL
+	)`, filters.AsOf).As("people")
+}
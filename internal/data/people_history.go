@@ -0,0 +1,159 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/barpav/demography/internal/actor"
+	"github.com/barpav/demography/internal/rest/models"
+)
+
+type querySetActor struct{}
+
+func (q querySetActor) text() string {
+	return `
+	SELECT set_config('demography.actor', $1, true);
+	`
+}
+
+type queryPersonDataHistory struct{}
+
+func (q queryPersonDataHistory) text() string {
+	return `
+	SELECT
+		id,
+		change::varchar,
+		version,
+		COALESCE(actor, ''),
+		changed_at,
+		COALESCE(before, 'null'::jsonb),
+		COALESCE(after, 'null'::jsonb)
+	FROM people_history
+	WHERE person_id = $1 AND id > $2
+	ORDER BY id
+	LIMIT $3;
+	`
+}
+
+// Changes of people data are made in a single transaction on behalf of the actor from context.
+// Errors of the change are returned as is.
+func (s *Storage) changeOnBehalf(ctx context.Context, change func(tx *sql.Tx) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer tx.Rollback()
+
+	_, err = tx.StmtContext(ctx, s.queries[querySetActor{}]).ExecContext(ctx, actor.FromContext(ctx))
+
+	if err != nil {
+		return fmt.Errorf("failed to set actor of changes: %w", err)
+	}
+
+	err = change(tx)
+
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+
+	if err != nil {
+		return fmt.Errorf("failed to commit changes: %w", err)
+	}
+
+	return nil
+}
+
+// Changes of person data (including purged one), paged by change id.
+func (s *Storage) PersonDataHistoryV1(ctx context.Context, id, after int64, limit int) (*models.PersonDataHistoryV1, error) {
+	rows, err := s.queries[queryPersonDataHistory{}].QueryContext(ctx, id, after, limit)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute sql query for person data history (v1): %w", err)
+	}
+
+	defer rows.Close()
+
+	result := &models.PersonDataHistoryV1{Data: make([]*models.PersonDataChangeV1, 0, limit)}
+
+	for rows.Next() {
+		change := &models.PersonDataChangeV1{}
+		var before, after []byte
+		err = rows.Scan(&change.Id, &change.Change, &change.Version, &change.Actor, &change.ChangedAt, &before, &after)
+
+		if err == nil {
+			change.ChangedAt = change.ChangedAt.UTC()
+			change.Before, change.After, err = changedFields(before, after)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to process sql query result for person data history (v1): %w", err)
+		}
+
+		result.Data = append(result.Data, change)
+	}
+
+	err = rows.Err()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to process sql query results for person data history (v1): %w", err)
+	}
+
+	result.Total = len(result.Data)
+
+	return result, nil
+}
+
+// Compares snapshots of people table row, only changed fields are returned (by schema names).
+// Empty values (nulls and empty arrays) are omitted.
+func changedFields(beforeSnapshot, afterSnapshot []byte) (before, after map[string]any, err error) {
+	var oldRow, newRow map[string]any
+
+	if err = json.Unmarshal(beforeSnapshot, &oldRow); err != nil {
+		return nil, nil, err
+	}
+
+	if err = json.Unmarshal(afterSnapshot, &newRow); err != nil {
+		return nil, nil, err
+	}
+
+	for column, field := range schemaFields {
+		oldValue, newValue := emptyToNil(oldRow[column]), emptyToNil(newRow[column])
+
+		if reflect.DeepEqual(oldValue, newValue) {
+			continue
+		}
+
+		if oldValue != nil {
+			if before == nil {
+				before = make(map[string]any)
+			}
+
+			before[field] = oldValue
+		}
+
+		if newValue != nil {
+			if after == nil {
+				after = make(map[string]any)
+			}
+
+			after[field] = newValue
+		}
+	}
+
+	return before, after, nil
+}
+
+func emptyToNil(value any) any {
+	if array, ok := value.([]any); ok && len(array) == 0 {
+		return nil
+	}
+
+	return value
+}
@@ -0,0 +1,23 @@
+package data
+
+import (
+	"time"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+)
+
+// Age calculated from birth date at the moment takes precedence over the stored one,
+// so every query reporting or filtering by age must use this expression (NULL if age is unknown).
+func personAgeColumn(moment string) string {
+	return `COALESCE(date_part('year', age(` + moment + `, birth_date))::integer, age)`
+}
+
+// Person age at the moment, current one if the moment is not specified.
+func personAge(asOf time.Time) exp.LiteralExpression {
+	if asOf.IsZero() {
+		return goqu.L(personAgeColumn("current_date"))
+	}
+
+	return goqu.L(personAgeColumn("?::timestamptz"), asOf)
+}
@@ -0,0 +1,62 @@
+package data
+
+import (
+	"context"
+	"fmt"
+)
+
+type queryPersonDataExists struct{}
+
+func (q queryPersonDataExists) text() string {
+	return `
+	SELECT EXISTS (SELECT 1 FROM people WHERE id = $1 AND deleted_at IS NULL);
+	`
+}
+
+type ErrPersonDataVersionMismatch struct{}
+
+// Expected versions of person data as query argument, NULL means unconditional change.
+func versionsArg(versions []int) any {
+	if len(versions) == 0 {
+		return nil
+	}
+
+	return versions
+}
+
+// Explains why conditional (with expected versions) or unconditional change of person data affected nothing.
+func (s *Storage) personDataNotChanged(ctx context.Context, id int64, versions []int) error {
+	if len(versions) == 0 {
+		return ErrPersonDataNotFound{}
+	}
+
+	exists, err := s.PersonDataExists(ctx, id)
+
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		return ErrPersonDataNotFound{}
+	}
+
+	return ErrPersonDataVersionMismatch{}
+}
+
+// Deleted and purged person data doesn't exist.
+func (s *Storage) PersonDataExists(ctx context.Context, id int64) (exists bool, err error) {
+	err = s.queries[queryPersonDataExists{}].QueryRowContext(ctx, id).Scan(&exists)
+
+	if err != nil {
+		return false, fmt.Errorf("failed to check person data existence: %w", err)
+	}
+
+	return exists, nil
+}
+
+func (e ErrPersonDataVersionMismatch) Error() string {
+	return "person data version mismatch"
+}
+
+func (e ErrPersonDataVersionMismatch) ImplementsPersonDataVersionMismatchError() {
+}
@@ -0,0 +1,70 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/barpav/demography/internal/actor"
+	"github.com/rs/zerolog/log"
+)
+
+type queryPurgeDeletedPeople struct{}
+
+func (q queryPurgeDeletedPeople) text() string {
+	return `
+	DELETE FROM people WHERE deleted_at < $1;
+	`
+}
+
+// Background job of the microservice, not started for one-off commands (e.g. API keys management).
+// Stopped on storage closing.
+func (s *Storage) StartPurging() {
+	s.stopPurge = make(chan struct{})
+	go s.purgeDeletedPeopleRegularly()
+}
+
+// Regularly hard-deletes people data which has been deleted longer than retention period ago.
+func (s *Storage) purgeDeletedPeopleRegularly() {
+	ticker := time.NewTicker(s.cfg.purgeInterval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := s.purgeDeletedPeople()
+
+		if err != nil {
+			log.Err(err).Msg("Failed to purge deleted people data.")
+		} else if purged != 0 {
+			log.Info().Msg(fmt.Sprintf("Deleted people data purged: %d records.", purged))
+		}
+
+		select {
+		case <-ticker.C:
+		case <-s.stopPurge:
+			return
+		}
+	}
+}
+
+func (s *Storage) purgeDeletedPeople() (int64, error) {
+	ctx, cancel := context.WithTimeout(actor.NewContext(context.Background(), actor.System), s.cfg.purgeInterval)
+	defer cancel()
+
+	var purged int64
+	err := s.changeOnBehalf(ctx, func(tx *sql.Tx) error {
+		result, err := tx.StmtContext(ctx, s.queries[queryPurgeDeletedPeople{}]).ExecContext(ctx, time.Now().Add(-s.cfg.deletedRetention))
+
+		if err == nil {
+			purged, err = result.RowsAffected()
+		}
+
+		return err
+	})
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge deleted people data: %w", err)
+	}
+
+	return purged, nil
+}
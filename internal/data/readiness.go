@@ -0,0 +1,40 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Storage is ready if database is reachable and prepared queries are still valid
+// (e.g. not broken by schema changes since they were prepared). Readiness is checked frequently,
+// so only the query selecting all person data is executed (it finds nothing).
+func (s *Storage) Ready(ctx context.Context) (err error) {
+	if s.db == nil {
+		return errors.New("storage is not open")
+	}
+
+	err = s.db.PingContext(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	for _, q := range queriesToPrepare() {
+		if s.queries[q] == nil {
+			return fmt.Errorf("query %T is not prepared", q)
+		}
+	}
+
+	rows, err := s.queries[queryGetEnrichedPersonDataV2{}].QueryContext(ctx, 0)
+
+	if err == nil {
+		err = rows.Close()
+	}
+
+	if err != nil {
+		return fmt.Errorf("prepared query %T is invalid: %w", queryGetEnrichedPersonDataV2{}, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,35 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+type queryRestorePersonData struct{}
+
+func (q queryRestorePersonData) text() string {
+	return `
+	UPDATE people SET deleted_at = NULL
+	WHERE id = $1 AND deleted_at IS NOT NULL
+	RETURNING version;
+	`
+}
+
+// Only deleted (not yet purged) person data can be restored. Returns new version of person data.
+func (s *Storage) RestorePersonData(ctx context.Context, id int64) (int, error) {
+	var version int
+	err := s.changeOnBehalf(ctx, func(tx *sql.Tx) error {
+		return tx.StmtContext(ctx, s.queries[queryRestorePersonData{}]).QueryRowContext(ctx, id).Scan(&version)
+	})
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, ErrPersonDataNotFound{}
+		}
+
+		return 0, fmt.Errorf("failed to restore person data: %w", err)
+	}
+
+	return version, nil
+}
@@ -6,50 +6,13 @@ import (
 	"fmt"
 
 	"github.com/barpav/demography/internal/rest/models"
+	"github.com/barpav/demography/internal/translit"
 	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
 )
 
 func (s *Storage) SearchResultV1(ctx context.Context, filters *models.SearchFilters) (result *models.SearchResultV1, err error) {
-	builder := goqu.Select(
-		"id",
-		"surname",
-		"person_name",
-		goqu.COALESCE(goqu.C("patronymic"), ""),
-		goqu.COALESCE(goqu.C("age"), 0),
-		goqu.COALESCE(goqu.C("gender").Cast("varchar"), ""),
-		goqu.COALESCE(goqu.C("country"), ""),
-	).From("people")
-
-	if filters.After != 0 {
-		builder = builder.Where(goqu.C("id").Gt(filters.After))
-	}
-
-	if filters.Surname != "" {
-		builder = builder.Where(goqu.C("surname").Eq(filters.Surname))
-	}
-
-	if filters.Name != "" {
-		builder = builder.Where(goqu.C("person_name").Eq(filters.Name))
-	}
-
-	if filters.Patronymic != "" {
-		builder = builder.Where(goqu.C("patronymic").Eq(filters.Patronymic))
-	}
-
-	if filters.Age != 0 {
-		builder = builder.Where(goqu.C("age").Eq(filters.Age))
-	}
-
-	if filters.Gender != "" {
-		builder = builder.Where(goqu.C("gender").Eq(filters.Gender))
-	}
-
-	if filters.Country != "" {
-		builder = builder.Where(goqu.C("country").Eq(filters.Country))
-	}
-
-	builder = builder.Order(goqu.C("id").Asc())
-	builder = builder.Limit(uint(filters.Limit))
+	builder := searchQueryV1(filters).Limit(uint(filters.Limit))
 
 	var query string
 	query, _, err = builder.ToSQL()
@@ -70,16 +33,8 @@ func (s *Storage) SearchResultV1(ctx context.Context, filters *models.SearchFilt
 	result = &models.SearchResultV1{Data: make([]*models.EnrichedPersonDataV1, 0, filters.Limit)}
 
 	for rows.Next() {
-		info := &models.EnrichedPersonDataV1{}
-		err = rows.Scan(
-			&info.Id,
-			&info.Surname,
-			&info.Name,
-			&info.Patronymic,
-			&info.Age,
-			&info.Gender,
-			&info.Country,
-		)
+		var info *models.EnrichedPersonDataV1
+		info, err = scanSearchResultV1(rows)
 
 		if err != nil {
 			return nil, fmt.Errorf("failed to process sql query result for search result (v1): %w", err)
@@ -98,3 +53,80 @@ func (s *Storage) SearchResultV1(ctx context.Context, filters *models.SearchFilt
 
 	return result, err
 }
+
+// Unpaged search query, ordered by id.
+func searchQueryV1(filters *models.SearchFilters) *goqu.SelectDataset {
+	builder := goqu.Select(
+		"id",
+		"surname",
+		"person_name",
+		goqu.COALESCE(goqu.C("patronymic"), ""),
+		goqu.COALESCE(personAge(filters.AsOf), 0),
+		genderV1,
+		goqu.COALESCE(goqu.C("country"), ""),
+	).From(peopleTable(filters))
+
+	if filters.After != 0 {
+		builder = builder.Where(goqu.C("id").Gt(filters.After))
+	}
+
+	builder = builder.Where(personFilters(filters)...)
+
+	return builder.Order(goqu.C("id").Asc())
+}
+
+func scanSearchResultV1(rows *sql.Rows) (info *models.EnrichedPersonDataV1, err error) {
+	info = &models.EnrichedPersonDataV1{}
+	err = rows.Scan(
+		&info.Id,
+		&info.Surname,
+		&info.Name,
+		&info.Patronymic,
+		&info.Age,
+		&info.Gender,
+		&info.Country,
+	)
+
+	return info, err
+}
+
+// Filters by person data, common for search and analytics queries. Deleted people data is excluded.
+// In transliteration mode names are compared by normalized (script and case independent) columns.
+func personFilters(filters *models.SearchFilters) (expressions []exp.Expression) {
+	expressions = append(expressions, goqu.C("deleted_at").IsNull())
+
+	columns := []struct {
+		name  string
+		value string
+	}{
+		{"surname", filters.Surname},
+		{"person_name", filters.Name},
+		{"patronymic", filters.Patronymic},
+	}
+
+	for _, c := range columns {
+		if c.value == "" {
+			continue
+		}
+
+		if filters.Translit {
+			expressions = append(expressions, goqu.C(c.name+"_normalized").Eq(translit.Normalize(c.value)))
+		} else {
+			expressions = append(expressions, goqu.C(c.name).Eq(c.value))
+		}
+	}
+
+	if filters.Age != 0 {
+		expressions = append(expressions, personAge(filters.AsOf).Eq(filters.Age))
+	}
+
+	if filters.Gender != "" {
+		expressions = append(expressions, personGender.Eq(filters.Gender))
+	}
+
+	if filters.Country != "" {
+		expressions = append(expressions, goqu.C("country").Eq(filters.Country))
+	}
+
+	return expressions
+}
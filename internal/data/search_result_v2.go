@@ -0,0 +1,94 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/barpav/demography/internal/rest/models"
+	"github.com/doug-martin/goqu/v9"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func (s *Storage) SearchResultV2(ctx context.Context, filters *models.SearchFilters) (result *models.SearchResultV2, err error) {
+	builder := searchQueryV2(filters).Limit(uint(filters.Limit))
+
+	var query string
+	query, _, err = builder.ToSQL()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sql query text for search result (v2): %w", err)
+	}
+
+	var rows *sql.Rows
+	rows, err = s.db.QueryContext(ctx, query)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute sql query for search result (v2): %w", err)
+	}
+
+	defer rows.Close()
+
+	result = &models.SearchResultV2{Data: make([]*models.EnrichedPersonDataV2, 0, filters.Limit)}
+	types := pgtype.NewMap() // for arrays
+
+	for rows.Next() {
+		info := &models.EnrichedPersonDataV2{}
+		err = rows.Scan(
+			&info.Id,
+			&info.Surname,
+			&info.Name,
+			&info.Patronymic,
+			types.SQLScanner(&info.MiddleNames),
+			&info.BirthDate,
+			&info.Age,
+			&info.Gender,
+			&info.GenderSource,
+			&info.Country,
+			types.SQLScanner(&info.Nationalities),
+			types.SQLScanner(&info.Tags),
+		)
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to process sql query result for search result (v2): %w", err)
+		}
+
+		result.Data = append(result.Data, info)
+	}
+
+	err = rows.Err()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to process sql query results for search result (v2): %w", err)
+	}
+
+	result.Total = len(result.Data)
+
+	return result, err
+}
+
+// Unpaged search query, ordered by id.
+func searchQueryV2(filters *models.SearchFilters) *goqu.SelectDataset {
+	builder := goqu.Select(
+		"id",
+		"surname",
+		"person_name",
+		goqu.COALESCE(goqu.C("patronymic"), ""),
+		"middle_names",
+		goqu.COALESCE(goqu.L("to_char(birth_date, 'YYYY-MM-DD')"), ""),
+		goqu.COALESCE(personAge(filters.AsOf), 0),
+		goqu.Cast(personGender, "varchar"),
+		goqu.COALESCE(goqu.C("gender_source").Cast("varchar"), ""),
+		goqu.COALESCE(goqu.C("country"), ""),
+		"nationalities",
+		"tags",
+	).From(peopleTable(filters))
+
+	if filters.After != 0 {
+		builder = builder.Where(goqu.C("id").Gt(filters.After))
+	}
+
+	builder = builder.Where(personFilters(filters)...)
+
+	return builder.Order(goqu.C("id").Asc())
+}
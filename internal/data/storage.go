@@ -12,9 +12,10 @@ import (
 )
 
 type Storage struct {
-	db      *sql.DB
-	cfg     *config
-	queries map[query]*sql.Stmt
+	db        *sql.DB
+	cfg       *config
+	queries   map[query]*sql.Stmt
+	stopPurge chan struct{}
 }
 
 type query interface {
@@ -25,8 +26,28 @@ func queriesToPrepare() []query {
 	return []query{
 		queryCreateNewPersonDataV1{},
 		queryGetEnrichedPersonDataV1{},
+		queryCreateNewPersonDataV2{},
+		queryGetEnrichedPersonDataV2{},
+		queryGetEnrichedPersonDataV1AsOf{},
+		queryGetEnrichedPersonDataV2AsOf{},
+		queryHistoryStart{},
 		queryUpdatePersonDataV1{},
+		queryUpdatePersonDataV2{},
 		queryDeletePersonData{},
+		queryRestorePersonData{},
+		queryPurgeDeletedPeople{},
+		querySetActor{},
+		queryPersonDataHistory{},
+		queryGetAPIKey{},
+		queryCreateAPIKey{},
+		queryRevokeAPIKey{},
+		queryGetAPIKeys{},
+		queryPersonDataExists{},
+		queryPurgeExpiredIdempotencyKeys{},
+		queryReserveIdempotencyKey{},
+		queryGetIdempotentResponse{},
+		querySaveIdempotentResponse{},
+		queryReleaseIdempotencyKey{},
 	}
 }
 
@@ -47,6 +68,10 @@ func (s *Storage) Close(ctx context.Context) (err error) {
 	var closeErr error
 	closed := make(chan struct{}, 1)
 
+	if s.stopPurge != nil {
+		close(s.stopPurge)
+	}
+
 	go func() {
 		for _, stmt := range s.queries {
 			if stmt != nil {
@@ -2,6 +2,7 @@ package data
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 
 	"github.com/barpav/demography/internal/rest/models"
@@ -16,32 +17,46 @@ func (q queryUpdatePersonDataV1) text() string {
 		person_name = $2,
 		patronymic = NULLIF($3, ''),
 		age = NULLIF($4, 0),
-		gender = NULLIF($5, '')::gender,
+		birth_date = CASE WHEN $4 = date_part('year', age(current_date, birth_date)) THEN birth_date END, -- kept if consistent
+		gender = CASE
+			WHEN $5::text <> '' THEN $5::text::gender
+			WHEN gender IN ('other', 'unknown') THEN gender -- not shown in version 1
+			ELSE 'unknown'
+		END,
+		gender_source = CASE
+			WHEN $5::text <> '' AND $5::text::gender IS DISTINCT FROM gender THEN 'declared'
+			WHEN $5::text <> '' OR gender IN ('other', 'unknown') THEN gender_source
+		END,
 		country = NULLIF($6, '')
-	WHERE id = $7;
+	WHERE id = $7 AND deleted_at IS NULL AND ($8::integer[] IS NULL OR version = ANY($8::integer[]))
+	RETURNING version;
 	`
 }
 
 type ErrPersonDataNotFound struct{}
 
-func (s *Storage) UpdatePersonDataV1(ctx context.Context, id int64, data *models.EditedPersonDataV1) error {
-	result, err := s.queries[queryUpdatePersonDataV1{}].ExecContext(ctx,
-		data.Surname, data.Name, data.Patronymic, data.Age, data.Gender, data.Country, id)
-
-	var updated int64
-	if err == nil {
-		updated, err = result.RowsAffected()
-	}
+// Person data is updated only if its version is one of expected (no versions means unconditional update).
+// Returns new version of person data.
+func (s *Storage) UpdatePersonDataV1(ctx context.Context, id int64, data *models.EditedPersonDataV1, versions []int) (int, error) {
+	var newVersion int
+	err := s.changeOnBehalf(ctx, func(tx *sql.Tx) error {
+		return tx.StmtContext(ctx, s.queries[queryUpdatePersonDataV1{}]).QueryRowContext(ctx,
+			data.Surname, data.Name, data.Patronymic, data.Age, data.Gender, data.Country, id, versionsArg(versions)).Scan(&newVersion)
+	})
 
 	if err != nil {
-		return fmt.Errorf("failed to update person data (v1): %w", err)
-	}
+		if err == sql.ErrNoRows {
+			return 0, s.personDataNotChanged(ctx, id, versions)
+		}
+
+		if violation, ok := constraintViolation(err); ok {
+			return 0, violation
+		}
 
-	if updated == 0 {
-		return ErrPersonDataNotFound{}
+		return 0, fmt.Errorf("failed to update person data (v1): %w", err)
 	}
 
-	return nil
+	return newVersion, nil
 }
 
 func (e ErrPersonDataNotFound) Error() string {
@@ -0,0 +1,59 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/barpav/demography/internal/rest/models"
+)
+
+type queryUpdatePersonDataV2 struct{}
+
+func (q queryUpdatePersonDataV2) text() string {
+	return `
+	UPDATE people SET
+		surname = $1,
+		person_name = $2,
+		patronymic = NULLIF($3, ''),
+		middle_names = COALESCE($4::varchar[], '{}'),
+		birth_date = NULLIF($5, '')::date,
+		age = NULLIF($6, 0),
+		gender = COALESCE(NULLIF($7::text, '')::gender, 'unknown'),
+		gender_source = CASE
+			WHEN $7::text = '' THEN NULL
+			WHEN $7::text::gender IS DISTINCT FROM gender THEN 'declared'
+			ELSE gender_source
+		END,
+		country = NULLIF($8, ''),
+		nationalities = COALESCE($9::varchar[], '{}'),
+		tags = COALESCE($10::varchar[], '{}')
+	WHERE id = $11 AND deleted_at IS NULL AND ($12::integer[] IS NULL OR version = ANY($12::integer[]))
+	RETURNING version;
+	`
+}
+
+// Person data is updated only if its version is one of expected (no versions means unconditional update).
+// Returns new version of person data.
+func (s *Storage) UpdatePersonDataV2(ctx context.Context, id int64, data *models.EditedPersonDataV2, versions []int) (int, error) {
+	var newVersion int
+	err := s.changeOnBehalf(ctx, func(tx *sql.Tx) error {
+		return tx.StmtContext(ctx, s.queries[queryUpdatePersonDataV2{}]).QueryRowContext(ctx,
+			data.Surname, data.Name, data.Patronymic, data.MiddleNames, data.BirthDate, data.Age, data.Gender,
+			data.Country, data.Nationalities, data.Tags, id, versionsArg(versions)).Scan(&newVersion)
+	})
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, s.personDataNotChanged(ctx, id, versions)
+		}
+
+		if violation, ok := constraintViolation(err); ok {
+			return 0, violation
+		}
+
+		return 0, fmt.Errorf("failed to update person data (v2): %w", err)
+	}
+
+	return newVersion, nil
+}
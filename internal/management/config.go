@@ -0,0 +1,57 @@
+package management
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultPort            = "8081"
+	defaultCheckTimeoutMs  = 2000
+	defaultCheckStatistics = false
+	defaultDrainSeconds    = 10 // longer than interval of probes
+)
+
+const (
+	envVarPort            = "DMG_MANAGEMENT_PORT"
+	envVarCheckTimeoutMs  = "DMG_READINESS_TIMEOUT_MS"
+	envVarCheckStatistics = "DMG_READINESS_CHECK_STATISTICS"
+	envVarDrainSeconds    = "DMG_SHUTDOWN_DRAIN_SECONDS"
+)
+
+type config struct {
+	port            string
+	checkTimeout    int  // ms
+	checkStatistics bool // whether statistics services must be reachable for readiness
+	drainPeriod     time.Duration
+}
+
+func (c *config) Read() {
+	c.port = os.Getenv(envVarPort)
+
+	if c.port == "" {
+		c.port = defaultPort
+	}
+
+	var err error
+	c.checkTimeout, err = strconv.Atoi(os.Getenv(envVarCheckTimeoutMs))
+
+	if err != nil || c.checkTimeout <= 0 {
+		c.checkTimeout = defaultCheckTimeoutMs
+	}
+
+	c.checkStatistics, err = strconv.ParseBool(os.Getenv(envVarCheckStatistics))
+
+	if err != nil {
+		c.checkStatistics = defaultCheckStatistics
+	}
+
+	drainSeconds, err := strconv.Atoi(os.Getenv(envVarDrainSeconds))
+
+	if err != nil || drainSeconds < 0 {
+		drainSeconds = defaultDrainSeconds
+	}
+
+	c.drainPeriod = time.Duration(drainSeconds) * time.Second
+}
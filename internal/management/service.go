@@ -0,0 +1,161 @@
+// Package management serves health probes of the microservice on a separate port,
+// so that they are never exposed along with public API.
+package management
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+)
+
+const mimeTypeHealth = "application/health+json"
+
+// Health check results (https://datatracker.ietf.org/doc/html/draft-inadarei-api-health-check).
+const (
+	statusPass = "pass"
+	statusFail = "fail"
+)
+
+type Service struct {
+	shutdown chan struct{}
+	cfg      *config
+	server   *http.Server
+	stats    StatisticsProvider
+	storage  Storage
+	draining atomic.Bool // microservice is shutting down
+}
+
+//go:generate mockery --name Storage
+type Storage interface {
+	Ready(ctx context.Context) error
+}
+
+//go:generate mockery --name StatisticsProvider
+type StatisticsProvider interface {
+	Reachable(ctx context.Context) error
+}
+
+type health struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"` // component status or failure reason
+}
+
+func (s *Service) Start(storage Storage, stats StatisticsProvider) {
+	s.cfg = &config{}
+	s.cfg.Read()
+
+	s.storage, s.stats = storage, stats
+
+	s.server = &http.Server{
+		Addr:    fmt.Sprintf(":%s", s.cfg.port),
+		Handler: s.operations(),
+	}
+
+	s.shutdown = make(chan struct{}, 1)
+
+	go func() {
+		err := s.server.ListenAndServe()
+
+		if err != http.ErrServerClosed {
+			log.Err(err).Msg("Management HTTP server crashed.")
+		}
+
+		s.shutdown <- struct{}{}
+	}()
+}
+
+// Readiness fails from now on, so that no more requests are routed to the microservice.
+func (s *Service) Drain() {
+	s.draining.Store(true)
+}
+
+// Time for load balancers to notice failing readiness before the microservice stops serving requests.
+func (s *Service) DrainPeriod() time.Duration {
+	return s.cfg.drainPeriod
+}
+
+func (s *Service) Stop(ctx context.Context) (err error) {
+	err = s.server.Shutdown(ctx)
+
+	if err != nil {
+		err = fmt.Errorf("failed to stop management HTTP service: %w", err)
+	}
+
+	return err
+}
+
+func (s *Service) Shutdown() <-chan struct{} {
+	return s.shutdown
+}
+
+func (s *Service) operations() *chi.Mux {
+	ops := chi.NewRouter()
+
+	ops.Get("/healthz", s.alive)
+	ops.Get("/readyz", s.ready)
+
+	return ops
+}
+
+// Process is alive as long as it responds.
+func (s *Service) alive(w http.ResponseWriter, r *http.Request) {
+	respond(w, &health{Status: statusPass})
+}
+
+// Microservice is ready to serve requests if its dependencies are available.
+func (s *Service) ready(w http.ResponseWriter, r *http.Request) {
+	result := &health{Status: statusPass, Checks: make(map[string]string)}
+
+	if s.draining.Load() {
+		result.Status = statusFail
+		result.Checks["shutdown"] = "Microservice is shutting down."
+		respond(w, result)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), time.Duration(s.cfg.checkTimeout)*time.Millisecond)
+	defer cancel()
+
+	result.check("storage", s.storage.Ready(ctx))
+
+	if s.cfg.checkStatistics {
+		result.check("statistics", s.stats.Reachable(ctx))
+	}
+
+	respond(w, result)
+}
+
+func (h *health) check(component string, err error) {
+	if err == nil {
+		h.Checks[component] = statusPass
+		return
+	}
+
+	log.Err(err).Msg(fmt.Sprintf("Readiness check of %s failed.", component))
+
+	h.Status = statusFail
+	h.Checks[component] = err.Error()
+}
+
+func respond(w http.ResponseWriter, h *health) {
+	w.Header().Set("Content-Type", mimeTypeHealth)
+	w.Header().Set("Cache-Control", "no-store")
+
+	if h.Status == statusPass {
+		w.WriteHeader(http.StatusOK)
+	} else {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+
+	err := json.NewEncoder(w).Encode(h)
+
+	if err != nil {
+		log.Err(err).Msg("Failed to serialize health check result.")
+	}
+}
@@ -0,0 +1,139 @@
+package management
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/barpav/demography/internal/management/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_alive(t *testing.T) {
+	w := httptest.NewRecorder()
+	(&Service{}).operations().ServeHTTP(w, httptest.NewRequest("GET", "/healthz", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, mimeTypeHealth, w.Result().Header.Get("Content-Type"))
+	require.JSONEq(t, `{"status": "pass"}`, w.Body.String())
+}
+
+func TestService_ready(t *testing.T) {
+	type testService struct {
+		storage  Storage
+		stats    StatisticsProvider
+		cfg      *config
+		draining bool
+	}
+	tests := []struct {
+		name        string
+		testService testService
+		wantStatus  int
+		wantChecks  map[string]string
+	}{
+		{
+			name: "Ready (200)",
+			testService: testService{
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("Ready", mock.Anything).Return(nil)
+					return s
+				}(),
+				cfg: &config{checkTimeout: 1000},
+			},
+			wantStatus: http.StatusOK,
+			wantChecks: map[string]string{"storage": statusPass},
+		},
+		{
+			name: "Ready with statistics (200)",
+			testService: testService{
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("Ready", mock.Anything).Return(nil)
+					return s
+				}(),
+				stats: func() *mocks.StatisticsProvider {
+					s := mocks.NewStatisticsProvider(t)
+					s.On("Reachable", mock.Anything).Return(nil)
+					return s
+				}(),
+				cfg: &config{checkTimeout: 1000, checkStatistics: true},
+			},
+			wantStatus: http.StatusOK,
+			wantChecks: map[string]string{"storage": statusPass, "statistics": statusPass},
+		},
+		{
+			name: "Storage is not ready (503)",
+			testService: testService{
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("Ready", mock.Anything).Return(errors.New("test error"))
+					return s
+				}(),
+				cfg: &config{checkTimeout: 1000},
+			},
+			wantStatus: http.StatusServiceUnavailable,
+			wantChecks: map[string]string{"storage": "test error"},
+		},
+		{
+			name: "Statistics are unreachable (503)",
+			testService: testService{
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("Ready", mock.Anything).Return(nil)
+					return s
+				}(),
+				stats: func() *mocks.StatisticsProvider {
+					s := mocks.NewStatisticsProvider(t)
+					s.On("Reachable", mock.Anything).Return(errors.New("test error"))
+					return s
+				}(),
+				cfg: &config{checkTimeout: 1000, checkStatistics: true},
+			},
+			wantStatus: http.StatusServiceUnavailable,
+			wantChecks: map[string]string{"storage": statusPass, "statistics": "test error"},
+		},
+		{
+			name: "Shutting down (503)",
+			testService: testService{
+				cfg:      &config{checkTimeout: 1000},
+				draining: true,
+			},
+			wantStatus: http.StatusServiceUnavailable,
+			wantChecks: map[string]string{"shutdown": "Microservice is shutting down."},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Service{
+				storage: tt.testService.storage,
+				stats:   tt.testService.stats,
+				cfg:     tt.testService.cfg,
+			}
+
+			if tt.testService.draining {
+				s.Drain()
+			}
+
+			w := httptest.NewRecorder()
+			s.operations().ServeHTTP(w, httptest.NewRequest("GET", "/readyz", nil))
+
+			require.Equal(t, tt.wantStatus, w.Code)
+
+			result := &health{}
+			require.NoError(t, json.NewDecoder(w.Body).Decode(result))
+
+			if tt.wantStatus == http.StatusOK {
+				require.Equal(t, statusPass, result.Status)
+			} else {
+				require.Equal(t, statusFail, result.Status)
+			}
+
+			require.Equal(t, tt.wantChecks, result.Checks)
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/barpav/demography/internal/rest/models"
+	"github.com/barpav/demography/internal/rest/negotiation"
+	"github.com/rs/zerolog/log"
+)
+
+// https://barpav.github.io/demography-api/#/people/post_people_batch
+func (s *Service) addNewPeopleBatch(w http.ResponseWriter, r *http.Request) {
+	switch negotiation.ContentType(r.Header.Get("Content-Type"), models.MimeTypeNewPeopleBatchV1) {
+	case models.MimeTypeNewPeopleBatchV1:
+		s.addNewPeopleBatchV1(w, r)
+	default:
+		respondWithProblem(w, http.StatusUnsupportedMediaType)
+		return
+	}
+}
+
+// In transaction mode any item error rejects the whole batch, other items get 424 (Failed Dependency).
+func (s *Service) addNewPeopleBatchV1(w http.ResponseWriter, r *http.Request) {
+	batch := models.NewPeopleBatchV1{}
+	itemErrors, err := batch.Deserialize(r.Body)
+
+	if err != nil {
+		respondWithInvalidRequest(w, err)
+		return
+	}
+
+	ctx := r.Context()
+	transaction := batch.Mode == models.BatchModeTransaction
+	result := &models.BatchResultV1{Items: make([]*models.BatchItemResultV1, 0, len(batch.Items))}
+	valid := make([]*models.NewPersonDataV1, 0, len(batch.Items))
+	validItems := make([]*models.BatchItemResultV1, 0, len(batch.Items))
+
+	for i, itemErr := range itemErrors {
+		item := &models.BatchItemResultV1{}
+		result.Items = append(result.Items, item)
+
+		if itemErr != nil {
+			item.Status, item.Errors = http.StatusBadRequest, errorMessages(itemErr)
+			continue
+		}
+
+		valid = append(valid, batch.Items[i])
+		validItems = append(validItems, item)
+	}
+
+	if transaction && len(valid) < len(batch.Items) {
+		s.respondWithBatchResult(w, rejectedBatch(result))
+		return
+	}
+
+	// otherwise the batch would never be allowed
+	if s.recordLimits != nil && len(valid) > s.recordLimits.perMinute {
+		respondWithProblemDetail(w, http.StatusRequestEntityTooLarge, fmt.Sprintf(
+			"Batch cannot contain more than %d valid items due to rate limit of records.", s.recordLimits.perMinute))
+		return
+	}
+
+	if !s.takeRecords(w, r, len(valid)) {
+		respondWithRateLimitExceeded(w, s.recordLimits, "records")
+		return
+	}
+
+	enriched, errs := s.enrichedPeopleDataV1(ctx, valid)
+	toSave := make([]*models.EnrichedPersonDataV1, 0, len(enriched))
+	toSaveItems := make([]*models.BatchItemResultV1, 0, len(enriched))
+
+	for i, enrichErr := range errs {
+		if enrichErr != nil {
+			log.Err(enrichErr).Msg("Failed to receive enriched person data (v1).")
+			validItems[i].Status, validItems[i].Errors = http.StatusInternalServerError, []string{"Failed to enrich person data."}
+			continue
+		}
+
+		toSave = append(toSave, enriched[i])
+		toSaveItems = append(toSaveItems, validItems[i])
+	}
+
+	if transaction {
+		if len(toSave) < len(valid) {
+			s.respondWithBatchResult(w, rejectedBatch(result))
+			return
+		}
+
+		err = s.storage.CreateNewPeopleDataV1(ctx, toSave)
+
+		if violation, ok := err.(ErrPeopleDataConstraintViolation); ok {
+			savedItem(toSaveItems[violation.ViolatingItem()], nil, violation)
+			s.respondWithBatchResult(w, rejectedBatch(result))
+			return
+		}
+
+		if err != nil {
+			log.Err(err).Msg("Failed to save new people data (v1).")
+		}
+
+		for i, data := range toSave {
+			savedItem(toSaveItems[i], data, err)
+		}
+	} else {
+		for i, data := range toSave {
+			err = s.storage.CreateNewPersonDataV1(ctx, data)
+
+			if _, ok := err.(ErrPersonDataConstraintViolation); !ok && err != nil {
+				log.Err(err).Msg("Failed to save new person data (v1).")
+			}
+
+			savedItem(toSaveItems[i], data, err)
+		}
+	}
+
+	s.respondWithBatchResult(w, result)
+}
+
+func savedItem(item *models.BatchItemResultV1, data *models.EnrichedPersonDataV1, err error) {
+	if violation, ok := err.(ErrPersonDataConstraintViolation); ok {
+		_, reason := violation.InvalidField()
+		item.Status, item.Errors = http.StatusUnprocessableEntity, []string{reason}
+	} else if err != nil {
+		item.Status, item.Errors = http.StatusInternalServerError, []string{"Failed to save person data."}
+	} else {
+		item.Status, item.Data = http.StatusCreated, data
+	}
+}
+
+type ErrPeopleDataConstraintViolation interface {
+	ErrPersonDataConstraintViolation
+	ViolatingItem() int // index of saved item
+}
+
+// Items without own errors are failed due to errors in other items.
+func rejectedBatch(result *models.BatchResultV1) *models.BatchResultV1 {
+	for _, item := range result.Items {
+		if item.Status == 0 {
+			item.Status, item.Errors = http.StatusFailedDependency, []string{"Batch is rejected due to errors in other items."}
+		}
+	}
+
+	return result
+}
+
+func (s *Service) respondWithBatchResult(w http.ResponseWriter, result *models.BatchResultV1) {
+	w.Header().Set("Content-Type", models.MimeTypeBatchResultV1)
+	w.WriteHeader(http.StatusMultiStatus)
+	err := json.NewEncoder(w).Encode(result)
+
+	if err != nil {
+		log.Err(err).Msg("Failed to serialize batch result (v1).")
+		return
+	}
+
+	created := 0
+
+	for _, item := range result.Items {
+		if item.Status == http.StatusCreated {
+			created++
+		}
+	}
+
+	log.Info().Msg(fmt.Sprintf("Batch of %d people data processed: %d created.", len(result.Items), created))
+}
@@ -0,0 +1,313 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/barpav/demography/internal/rest/mocks"
+	"github.com/barpav/demography/internal/rest/models"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_addNewPeopleBatch(t *testing.T) {
+	statistics := func() *mocks.StatisticsProvider {
+		s := mocks.NewStatisticsProvider(t)
+		s.On("AgeByName", mock.Anything).Return(50, nil)
+		s.On("GenderByName", mock.Anything).Return("male", nil)
+		s.On("CountryByName", mock.Anything).Return("RU", nil)
+		return s
+	}
+
+	enriched := func(id int64, surname, name string) *models.EnrichedPersonDataV1 {
+		return &models.EnrichedPersonDataV1{
+			Id:      id,
+			Surname: surname,
+			Name:    name,
+			Age:     50,
+			Gender:  "male",
+			Country: "RU",
+		}
+	}
+
+	type testService struct {
+		cfg          *config
+		stats        StatisticsProvider
+		storage      Storage
+		recordLimits *rateLimiter
+	}
+	type args struct {
+		w *httptest.ResponseRecorder
+		r *http.Request
+	}
+	tests := []struct {
+		name        string
+		testService testService
+		args        args
+		wantHeaders map[string]string
+		wantBody    *models.BatchResultV1
+		wantStatus  int
+	}{
+		{
+			name: "Created in transaction (207)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					batch := `{"items": [{"surname": "Ivanov", "name": "Ivan"}, {"surname": "Petrov", "name": "Petr"}]}`
+					r := httptest.NewRequest("POST", "/v1/people:batch", strings.NewReader(batch))
+					r.Header.Set("Content-Type", models.MimeTypeNewPeopleBatchV1)
+					return r
+				}(),
+			},
+			testService: testService{
+				cfg:   &config{statsTimeout: 3000, enrichmentConcurrency: 5},
+				stats: statistics(),
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("CreateNewPeopleDataV1", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+						for i, data := range args.Get(1).([]*models.EnrichedPersonDataV1) {
+							data.Id = int64(i + 1)
+						}
+					}).Return(nil)
+					return s
+				}(),
+			},
+			wantHeaders: map[string]string{
+				"Content-Type": models.MimeTypeBatchResultV1,
+			},
+			wantBody: &models.BatchResultV1{
+				Items: []*models.BatchItemResultV1{
+					{Status: http.StatusCreated, Data: enriched(1, "Ivanov", "Ivan")},
+					{Status: http.StatusCreated, Data: enriched(2, "Petrov", "Petr")},
+				},
+			},
+			wantStatus: http.StatusMultiStatus,
+		},
+		{
+			name: "Transaction rejected due to invalid item (207)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					batch := `{"mode": "transaction", "items": [{"surname": "Ivanov", "name": "Ivan"}, {"name": "Petr"}]}`
+					r := httptest.NewRequest("POST", "/v1/people:batch", strings.NewReader(batch))
+					r.Header.Set("Content-Type", models.MimeTypeNewPeopleBatchV1)
+					return r
+				}(),
+			},
+			testService: testService{
+				cfg: &config{statsTimeout: 3000, enrichmentConcurrency: 5},
+			},
+			wantHeaders: map[string]string{
+				"Content-Type": models.MimeTypeBatchResultV1,
+			},
+			wantBody: &models.BatchResultV1{
+				Items: []*models.BatchItemResultV1{
+					{Status: http.StatusFailedDependency, Errors: []string{"Batch is rejected due to errors in other items."}},
+					{Status: http.StatusBadRequest, Errors: []string{"Person's surname must be specified."}},
+				},
+			},
+			wantStatus: http.StatusMultiStatus,
+		},
+		{
+			name: "Transaction rejected due to constraint violation (207)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					batch := `{"mode": "transaction", "items": [{"surname": "Ivanov", "name": "Ivan"}, {"surname": "Petrov", "name": "Petr"}]}`
+					r := httptest.NewRequest("POST", "/v1/people:batch", strings.NewReader(batch))
+					r.Header.Set("Content-Type", models.MimeTypeNewPeopleBatchV1)
+					return r
+				}(),
+			},
+			testService: testService{
+				cfg:   &config{statsTimeout: 3000, enrichmentConcurrency: 5},
+				stats: statistics(),
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("CreateNewPeopleDataV1", mock.Anything, mock.Anything).Return(ErrPeopleDataConstraintViolationTest{item: 1})
+					return s
+				}(),
+			},
+			wantHeaders: map[string]string{
+				"Content-Type": models.MimeTypeBatchResultV1,
+			},
+			wantBody: &models.BatchResultV1{
+				Items: []*models.BatchItemResultV1{
+					{Status: http.StatusFailedDependency, Errors: []string{"Batch is rejected due to errors in other items."}},
+					{Status: http.StatusUnprocessableEntity, Errors: []string{"Person's age must be between 0 and 150."}},
+				},
+			},
+			wantStatus: http.StatusMultiStatus,
+		},
+		{
+			name: "Best effort with item errors (207)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					batch := `{"mode": "best-effort", "items": [` +
+						`{"surname": "Ivanov", "name": "Ivan"}, {"name": "Petr"}, {"surname": "Sidorov", "name": "Ivan"}]}`
+					r := httptest.NewRequest("POST", "/v1/people:batch", strings.NewReader(batch))
+					r.Header.Set("Content-Type", models.MimeTypeNewPeopleBatchV1)
+					return r
+				}(),
+			},
+			testService: testService{
+				cfg:   &config{statsTimeout: 3000, enrichmentConcurrency: 1},
+				stats: statistics(),
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("CreateNewPersonDataV1", mock.Anything, mock.MatchedBy(func(data *models.EnrichedPersonDataV1) bool {
+						return data.Surname == "Ivanov"
+					})).Run(func(args mock.Arguments) {
+						args.Get(1).(*models.EnrichedPersonDataV1).Id = 1
+					}).Return(nil)
+					s.On("CreateNewPersonDataV1", mock.Anything, mock.MatchedBy(func(data *models.EnrichedPersonDataV1) bool {
+						return data.Surname == "Sidorov"
+					})).Return(errors.New("test"))
+					return s
+				}(),
+			},
+			wantHeaders: map[string]string{
+				"Content-Type": models.MimeTypeBatchResultV1,
+			},
+			wantBody: &models.BatchResultV1{
+				Items: []*models.BatchItemResultV1{
+					{Status: http.StatusCreated, Data: enriched(1, "Ivanov", "Ivan")},
+					{Status: http.StatusBadRequest, Errors: []string{"Person's surname must be specified."}},
+					{Status: http.StatusInternalServerError, Errors: []string{"Failed to save person data."}},
+				},
+			},
+			wantStatus: http.StatusMultiStatus,
+		},
+		{
+			name: "Records rate limit exceeded (429)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					batch := `{"items": [{"surname": "Ivanov", "name": "Ivan"}, {"surname": "Petrov", "name": "Petr"}]}`
+					r := httptest.NewRequest("POST", "/v1/people:batch", strings.NewReader(batch))
+					r.Header.Set("Content-Type", models.MimeTypeNewPeopleBatchV1)
+					return r
+				}(),
+			},
+			testService: testService{
+				cfg: &config{statsTimeout: 3000, enrichmentConcurrency: 5},
+				recordLimits: func() *rateLimiter {
+					l := newRateLimiter(2)
+					l.take("", 1, time.Now()) // actor is unknown
+					return l
+				}(),
+			},
+			wantHeaders: map[string]string{
+				"Retry-After": "30",
+			},
+			wantStatus: http.StatusTooManyRequests,
+		},
+		{
+			name: "Batch exceeds records rate limit (413)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					batch := `{"items": [{"surname": "Ivanov", "name": "Ivan"}, {"surname": "Petrov", "name": "Petr"}]}`
+					r := httptest.NewRequest("POST", "/v1/people:batch", strings.NewReader(batch))
+					r.Header.Set("Content-Type", models.MimeTypeNewPeopleBatchV1)
+					return r
+				}(),
+			},
+			testService: testService{
+				cfg:          &config{statsTimeout: 3000, enrichmentConcurrency: 5},
+				recordLimits: newRateLimiter(1),
+			},
+			wantHeaders: map[string]string{},
+			wantStatus:  http.StatusRequestEntityTooLarge,
+		},
+		{
+			name: "Incorrect batch (400)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					batch := `{"mode": "sometimes", "items": []}`
+					r := httptest.NewRequest("POST", "/v1/people:batch", strings.NewReader(batch))
+					r.Header.Set("Content-Type", models.MimeTypeNewPeopleBatchV1)
+					return r
+				}(),
+			},
+			wantHeaders: map[string]string{},
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			name: "Unsupported batch (415)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					r := httptest.NewRequest("POST", "/v1/people:batch", strings.NewReader("[]"))
+					r.Header.Set("Content-Type", "application/json")
+					return r
+				}(),
+			},
+			wantHeaders: map[string]string{},
+			wantStatus:  http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Service{
+				cfg:          tt.testService.cfg,
+				stats:        tt.testService.stats,
+				storage:      tt.testService.storage,
+				recordLimits: tt.testService.recordLimits,
+			}
+			s.addNewPeopleBatch(tt.args.w, tt.args.r)
+
+			for k, v := range tt.wantHeaders {
+				require.Equal(t, v, func() string {
+					h := tt.args.w.Result().Header
+					if h == nil {
+						return ""
+					}
+					v := h[k]
+					if len(v) == 0 {
+						return ""
+					}
+					return v[0]
+				}())
+			}
+
+			require.Equal(t, tt.wantStatus, tt.args.w.Code)
+
+			if tt.wantBody == nil {
+				return
+			}
+
+			var body *models.BatchResultV1
+			decoded := models.BatchResultV1{}
+			err := json.NewDecoder(tt.args.w.Body).Decode(&decoded)
+
+			if err != nil && err != io.EOF {
+				t.Fatal(err)
+			}
+
+			if err == nil {
+				body = &decoded
+			}
+
+			require.Equal(t, body, tt.wantBody)
+		})
+	}
+}
+
+type ErrPeopleDataConstraintViolationTest struct {
+	ErrPersonDataConstraintViolationTest
+	item int
+}
+
+func (e ErrPeopleDataConstraintViolationTest) ViolatingItem() int {
+	return e.item
+}
@@ -9,16 +9,20 @@ import (
 	"time"
 
 	"github.com/barpav/demography/internal/rest/models"
+	"github.com/barpav/demography/internal/rest/negotiation"
+	"github.com/barpav/demography/internal/translit"
 	"github.com/rs/zerolog/log"
 )
 
 // https://barpav.github.io/demography-api/#/people/post_people
 func (s *Service) addNewPerson(w http.ResponseWriter, r *http.Request) {
-	switch r.Header.Get("Content-Type") {
+	switch negotiation.ContentType(r.Header.Get("Content-Type"), models.MimeTypeNewPersonDataV1, models.MimeTypeNewPersonDataV2) {
 	case models.MimeTypeNewPersonDataV1:
 		s.addNewPersonV1(w, r)
+	case models.MimeTypeNewPersonDataV2:
+		s.addNewPersonV2(w, r)
 	default:
-		w.WriteHeader(http.StatusUnsupportedMediaType)
+		respondWithProblem(w, http.StatusUnsupportedMediaType)
 		return
 	}
 }
@@ -28,7 +32,7 @@ func (s *Service) addNewPersonV1(w http.ResponseWriter, r *http.Request) {
 	err := personData.Deserialize(r.Body)
 
 	if err != nil {
-		http.Error(w, err.Error(), 400)
+		respondWithInvalidRequest(w, err)
 		return
 	}
 
@@ -39,19 +43,25 @@ func (s *Service) addNewPersonV1(w http.ResponseWriter, r *http.Request) {
 
 	if err != nil {
 		log.Err(err).Msg("Failed to receive enriched person data (v1).")
-		w.WriteHeader(http.StatusInternalServerError)
+		respondWithProblem(w, http.StatusInternalServerError)
 		return
 	}
 
 	err = s.storage.CreateNewPersonDataV1(ctx, fullData)
 
+	if violation, ok := err.(ErrPersonDataConstraintViolation); ok {
+		respondWithConstraintViolation(w, violation)
+		return
+	}
+
 	if err != nil {
 		log.Err(err).Msg("Failed to save new person data (v1).")
-		w.WriteHeader(http.StatusInternalServerError)
+		respondWithProblem(w, http.StatusInternalServerError)
 		return
 	}
 
 	w.Header().Set("Content-Type", models.MimeTypeEnrichedPersonDataV1)
+	w.Header().Set("ETag", entityTag(fullData.Version))
 	w.WriteHeader(http.StatusCreated)
 	err = json.NewEncoder(w).Encode(fullData)
 
@@ -64,27 +74,75 @@ func (s *Service) addNewPersonV1(w http.ResponseWriter, r *http.Request) {
 	log.Info().Msg(fmt.Sprintf("Person data with id '%d' created.", fullData.Id))
 }
 
-func (s *Service) enrichedPersonDataV1(ctx context.Context, data *models.NewPersonDataV1) (result *models.EnrichedPersonDataV1, err error) {
+func (s *Service) addNewPersonV2(w http.ResponseWriter, r *http.Request) {
+	personData := models.NewPersonDataV2{}
+	err := personData.Deserialize(r.Body)
+
+	if err != nil {
+		respondWithInvalidRequest(w, err)
+		return
+	}
+
+	ctx := r.Context()
+
+	var fullData *models.EnrichedPersonDataV2
+	fullData, err = s.enrichedPersonDataV2(ctx, &personData)
+
+	if err != nil {
+		log.Err(err).Msg("Failed to receive enriched person data (v2).")
+		respondWithProblem(w, http.StatusInternalServerError)
+		return
+	}
+
+	err = s.storage.CreateNewPersonDataV2(ctx, fullData)
+
+	if violation, ok := err.(ErrPersonDataConstraintViolation); ok {
+		respondWithConstraintViolation(w, violation)
+		return
+	}
+
+	if err != nil {
+		log.Err(err).Msg("Failed to save new person data (v2).")
+		respondWithProblem(w, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", models.MimeTypeEnrichedPersonDataV2)
+	w.Header().Set("ETag", entityTag(fullData.Version))
+	w.WriteHeader(http.StatusCreated)
+	err = json.NewEncoder(w).Encode(fullData)
+
+	if err != nil {
+		log.Err(err).Msg("Failed to serialize enriched person data (v2).")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	log.Info().Msg(fmt.Sprintf("Person data with id '%d' created (v2).", fullData.Id))
+}
+
+func (s *Service) enrichedPersonDataV1(ctx context.Context, data *models.NewPersonDataV1) (*models.EnrichedPersonDataV1, error) {
+	return s.enrichedPersonData(ctx, data, false, false)
+}
+
+// Age and gender statistics are not requested if they are already known (derived from birth date or declared).
+func (s *Service) enrichedPersonData(ctx context.Context, data *models.NewPersonDataV1, ageKnown, genderKnown bool) (
+	result *models.EnrichedPersonDataV1, err error) {
 	ctx, cancel := context.WithTimeout(ctx, time.Millisecond*time.Duration(s.cfg.statsTimeout))
 	defer cancel()
 
 	var age int
 	var gender, country string
 
+	// 3rd party statistics APIs understand only Latin names
+	name := translit.ToLatin(data.Name)
+
 	wg := &sync.WaitGroup{}
-	wg.Add(3)
 	done := make(chan struct{})
 	interrupt := make(chan struct{})
 
-	// waiting for confirmation from all 3rd parties (all or nothing)
-	go func() {
-		wg.Wait()
-		close(done)
-		log.Debug().Msg("enrichedPersonDataV1: all goroutines are finished")
-	}()
-
 	// receiving age statistics from 3rd party (retries in timeout range)
-	go func() {
+	receiveAge := func() {
 		var statsErr error
 		for {
 			select {
@@ -93,7 +151,7 @@ func (s *Service) enrichedPersonDataV1(ctx context.Context, data *models.NewPers
 				log.Debug().Msg("enrichedPersonDataV1: age receiving goroutine interrupted")
 				return
 			default:
-				age, statsErr = s.stats.AgeByName(data.Name)
+				age, statsErr = s.stats.AgeByName(name)
 				if statsErr == nil {
 					wg.Done()
 					log.Debug().Msg("enrichedPersonDataV1: age receiving goroutine completed")
@@ -103,10 +161,10 @@ func (s *Service) enrichedPersonDataV1(ctx context.Context, data *models.NewPers
 				}
 			}
 		}
-	}()
+	}
 
 	// receiving gender statistics from 3rd party (retries in timeout range)
-	go func() {
+	receiveGender := func() {
 		var statsErr error
 		for {
 			select {
@@ -115,7 +173,7 @@ func (s *Service) enrichedPersonDataV1(ctx context.Context, data *models.NewPers
 				log.Debug().Msg("enrichedPersonDataV1: gender receiving goroutine interrupted")
 				return
 			default:
-				gender, statsErr = s.stats.GenderByName(data.Name)
+				gender, statsErr = s.stats.GenderByName(name)
 				if statsErr == nil {
 					wg.Done()
 					log.Debug().Msg("enrichedPersonDataV1: gender receiving goroutine completed")
@@ -125,10 +183,10 @@ func (s *Service) enrichedPersonDataV1(ctx context.Context, data *models.NewPers
 				}
 			}
 		}
-	}()
+	}
 
 	// receiving country statistics from 3rd party (retries in timeout range)
-	go func() {
+	receiveCountry := func() {
 		var statsErr error
 		for {
 			select {
@@ -137,7 +195,7 @@ func (s *Service) enrichedPersonDataV1(ctx context.Context, data *models.NewPers
 				log.Debug().Msg("enrichedPersonDataV1: country receiving goroutine interrupted")
 				return
 			default:
-				country, statsErr = s.stats.CountryByName(data.Name)
+				country, statsErr = s.stats.CountryByName(name)
 				if statsErr == nil {
 					wg.Done()
 					log.Debug().Msg("enrichedPersonDataV1: country receiving goroutine completed")
@@ -147,6 +205,26 @@ func (s *Service) enrichedPersonDataV1(ctx context.Context, data *models.NewPers
 				}
 			}
 		}
+	}
+
+	if !ageKnown {
+		wg.Add(1)
+		go receiveAge()
+	}
+
+	if !genderKnown {
+		wg.Add(1)
+		go receiveGender()
+	}
+
+	wg.Add(1)
+	go receiveCountry()
+
+	// waiting for confirmation from all 3rd parties (all or nothing)
+	go func() {
+		wg.Wait()
+		close(done)
+		log.Debug().Msg("enrichedPersonDataV1: all goroutines are finished")
 	}()
 
 	select {
@@ -167,3 +245,73 @@ func (s *Service) enrichedPersonDataV1(ctx context.Context, data *models.NewPers
 
 	return result, nil
 }
+
+// Age derived from birth date and declared gender take precedence over statistics.
+func (s *Service) enrichedPersonDataV2(ctx context.Context, data *models.NewPersonDataV2) (*models.EnrichedPersonDataV2, error) {
+	age, ageKnown := data.Age(time.Now().UTC())
+	enriched, err := s.enrichedPersonData(ctx, &models.NewPersonDataV1{
+		Surname:    data.Surname,
+		Name:       data.Name,
+		Patronymic: data.Patronymic,
+	}, ageKnown, data.Gender != "")
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.EnrichedPersonDataV2{
+		Surname:       data.Surname,
+		Name:          data.Name,
+		Patronymic:    data.Patronymic,
+		MiddleNames:   data.MiddleNames,
+		BirthDate:     data.BirthDate,
+		Age:           enriched.Age,
+		Gender:        enriched.Gender,
+		GenderSource:  models.GenderSourceStatistics,
+		Country:       enriched.Country,
+		Nationalities: data.Nationalities,
+		Tags:          data.Tags,
+	}
+
+	if ageKnown {
+		result.Age = age
+	}
+
+	if data.Gender != "" {
+		result.Gender, result.GenderSource = data.Gender, models.GenderSourceDeclared
+	}
+
+	if result.Gender == "" {
+		result.Gender, result.GenderSource = models.GenderUnknown, "" // undetermined gender has no source
+	}
+
+	return result, nil
+}
+
+// Enrichment is performed concurrently, limited by configured number of simultaneous enrichments.
+// Returned errors correspond to people data (nil if enriched successfully).
+func (s *Service) enrichedPeopleDataV1(ctx context.Context, data []*models.NewPersonDataV1) (
+	result []*models.EnrichedPersonDataV1, errs []error) {
+	result = make([]*models.EnrichedPersonDataV1, len(data))
+	errs = make([]error, len(data))
+	semaphore := make(chan struct{}, s.cfg.enrichmentConcurrency)
+	wg := &sync.WaitGroup{}
+
+	for i, person := range data {
+		wg.Add(1)
+		semaphore <- struct{}{}
+
+		go func(i int, person *models.NewPersonDataV1) {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+
+			result[i], errs[i] = s.enrichedPersonDataV1(ctx, person)
+		}(i, person)
+	}
+
+	wg.Wait()
+
+	return result, errs
+}
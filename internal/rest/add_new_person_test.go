@@ -7,7 +7,9 @@ import (
 	"log"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/barpav/demography/internal/rest/mocks"
 	"github.com/barpav/demography/internal/rest/models"
@@ -64,12 +66,15 @@ func TestService_addNewPerson(t *testing.T) {
 				}(),
 				storage: func() *mocks.Storage {
 					s := mocks.NewStorage(t)
-					s.On("CreateNewPersonDataV1", mock.Anything, mock.Anything).Return(nil)
+					s.On("CreateNewPersonDataV1", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+						args.Get(1).(*models.EnrichedPersonDataV1).Version = 1
+					})
 					return s
 				}(),
 			},
 			wantHeaders: map[string]string{
 				"Content-Type": models.MimeTypeEnrichedPersonDataV1,
+				"ETag":         `"1"`,
 			},
 			wantBody: &models.EnrichedPersonDataV1{
 				Surname:    "Ivanov",
@@ -81,6 +86,84 @@ func TestService_addNewPerson(t *testing.T) {
 			},
 			wantStatus: http.StatusCreated,
 		},
+		{
+			name: "New person data in Cyrillic added (201)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					m := models.NewPersonDataV1{
+						Name:       "Иван",
+						Patronymic: "Иванович",
+						Surname:    "Иванов",
+					}
+					var buf bytes.Buffer
+					err := json.NewEncoder(&buf).Encode(m)
+					if err != nil {
+						log.Fatal(err)
+					}
+					r := httptest.NewRequest("POST", "/v1/people", &buf)
+					r.Header.Set("Content-Type", models.MimeTypeNewPersonDataV1)
+					return r
+				}(),
+			},
+			testService: testService{
+				cfg: &config{statsTimeout: 3000},
+				stats: func() *mocks.StatisticsProvider {
+					s := mocks.NewStatisticsProvider(t)
+					s.On("AgeByName", "Ivan").Return(50, nil)
+					s.On("GenderByName", "Ivan").Return("male", nil)
+					s.On("CountryByName", "Ivan").Return("RU", nil)
+					return s
+				}(),
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("CreateNewPersonDataV1", mock.Anything, mock.Anything).Return(nil)
+					return s
+				}(),
+			},
+			wantHeaders: map[string]string{
+				"Content-Type": models.MimeTypeEnrichedPersonDataV1,
+			},
+			wantBody: &models.EnrichedPersonDataV1{
+				Surname:    "Иванов",
+				Name:       "Иван",
+				Patronymic: "Иванович",
+				Age:        50,
+				Gender:     "male",
+				Country:    "RU",
+			},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name: "Data constraint violation (422)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					r := httptest.NewRequest("POST", "/v1/people", strings.NewReader(`{"surname": "Ivanov", "name": "Ivan"}`))
+					r.Header.Set("Content-Type", models.MimeTypeNewPersonDataV1)
+					return r
+				}(),
+			},
+			testService: testService{
+				cfg: &config{statsTimeout: 3000},
+				stats: func() *mocks.StatisticsProvider {
+					s := mocks.NewStatisticsProvider(t)
+					s.On("AgeByName", "Ivan").Return(151, nil)
+					s.On("GenderByName", "Ivan").Return("male", nil)
+					s.On("CountryByName", "Ivan").Return("RU", nil)
+					return s
+				}(),
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("CreateNewPersonDataV1", mock.Anything, mock.Anything).Return(ErrPersonDataConstraintViolationTest{})
+					return s
+				}(),
+			},
+			wantHeaders: map[string]string{
+				"Content-Type": models.MimeTypeProblem,
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
 		{
 			name: "Incomplete new person data (400)",
 			args: args{
@@ -143,7 +226,7 @@ func TestService_addNewPerson(t *testing.T) {
 					if h == nil {
 						return ""
 					}
-					v := h[k]
+					v := h.Values(k)
 					if len(v) == 0 {
 						return ""
 					}
@@ -173,3 +256,183 @@ func TestService_addNewPerson(t *testing.T) {
 		})
 	}
 }
+
+func TestService_addNewPersonV2(t *testing.T) {
+	birthDate := time.Now().UTC().AddDate(-30, 0, -1)
+	request := func(m any) *http.Request {
+		var buf bytes.Buffer
+		err := json.NewEncoder(&buf).Encode(m)
+		if err != nil {
+			log.Fatal(err)
+		}
+		r := httptest.NewRequest("POST", "/v1/people", &buf)
+		r.Header.Set("Content-Type", models.MimeTypeNewPersonDataV2)
+		return r
+	}
+
+	type testService struct {
+		stats   StatisticsProvider
+		storage Storage
+	}
+	type args struct {
+		w *httptest.ResponseRecorder
+		r *http.Request
+	}
+	tests := []struct {
+		name        string
+		testService testService
+		args        args
+		wantBody    *models.EnrichedPersonDataV2
+		wantStatus  int
+	}{
+		{
+			name: "New person data added, age derived from birth date (201)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: request(models.NewPersonDataV2{
+					Surname:       "Ivanov",
+					Name:          "Ivan",
+					MiddleNames:   []string{" Petr ", "Petr"},
+					BirthDate:     birthDate.Format(models.DateFormat),
+					Nationalities: []string{"ru", "BY"},
+					Tags:          []string{"employee"},
+				}),
+			},
+			testService: testService{
+				stats: func() *mocks.StatisticsProvider {
+					s := mocks.NewStatisticsProvider(t)
+					s.On("GenderByName", "Ivan").Return("male", nil)
+					s.On("CountryByName", "Ivan").Return("RU", nil)
+					return s
+				}(),
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("CreateNewPersonDataV2", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+						args.Get(1).(*models.EnrichedPersonDataV2).Id = 101
+					})
+					return s
+				}(),
+			},
+			wantBody: &models.EnrichedPersonDataV2{
+				Id:            101,
+				Surname:       "Ivanov",
+				Name:          "Ivan",
+				MiddleNames:   []string{"Petr"},
+				BirthDate:     birthDate.Format(models.DateFormat),
+				Age:           30,
+				Gender:        "male",
+				GenderSource:  models.GenderSourceStatistics,
+				Country:       "RU",
+				Nationalities: []string{"RU", "BY"},
+				Tags:          []string{"employee"},
+			},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name: "Declared gender (201)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: request(models.NewPersonDataV2{
+					Surname: "Ivanov",
+					Name:    "Alex",
+					Gender:  models.GenderOther,
+				}),
+			},
+			testService: testService{
+				stats: func() *mocks.StatisticsProvider {
+					s := mocks.NewStatisticsProvider(t)
+					s.On("AgeByName", "Alex").Return(40, nil)
+					s.On("CountryByName", "Alex").Return("", nil)
+					return s
+				}(),
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("CreateNewPersonDataV2", mock.Anything, mock.Anything).Return(nil)
+					return s
+				}(),
+			},
+			wantBody: &models.EnrichedPersonDataV2{
+				Surname:      "Ivanov",
+				Name:         "Alex",
+				Age:          40,
+				Gender:       models.GenderOther,
+				GenderSource: models.GenderSourceDeclared,
+			},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name: "Gender not determined by statistics (201)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: request(models.NewPersonDataV2{
+					Surname: "Ivanov",
+					Name:    "Alex",
+				}),
+			},
+			testService: testService{
+				stats: func() *mocks.StatisticsProvider {
+					s := mocks.NewStatisticsProvider(t)
+					s.On("AgeByName", "Alex").Return(40, nil)
+					s.On("GenderByName", "Alex").Return("", nil)
+					s.On("CountryByName", "Alex").Return("", nil)
+					return s
+				}(),
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("CreateNewPersonDataV2", mock.Anything, mock.Anything).Return(nil)
+					return s
+				}(),
+			},
+			wantBody: &models.EnrichedPersonDataV2{
+				Surname: "Ivanov",
+				Name:    "Alex",
+				Age:     40,
+				Gender:  models.GenderUnknown,
+			},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name: "Incorrect extended attributes (400)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: request(models.NewPersonDataV2{
+					Surname:       "Ivanov",
+					Name:          "Ivan",
+					BirthDate:     "01.01.1990",
+					Gender:        "none",
+					Nationalities: []string{"RUS"},
+					Tags:          []string{""},
+				}),
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Service{
+				cfg:     &config{statsTimeout: 3000},
+				stats:   tt.testService.stats,
+				storage: tt.testService.storage,
+			}
+			s.addNewPerson(tt.args.w, tt.args.r)
+
+			require.Equal(t, tt.wantStatus, tt.args.w.Code)
+
+			if tt.wantBody == nil {
+				return
+			}
+
+			require.Equal(t, models.MimeTypeEnrichedPersonDataV2, tt.args.w.Result().Header.Get("Content-Type"))
+
+			body := &models.EnrichedPersonDataV2{}
+			err := json.NewDecoder(tt.args.w.Body).Decode(body)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			require.Equal(t, tt.wantBody, body)
+		})
+	}
+}
@@ -0,0 +1,220 @@
+package rest
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/barpav/demography/internal/actor"
+	"github.com/rs/zerolog/log"
+)
+
+// Scopes of access to operations (see Service.operations).
+const (
+	ScopePeopleRead   = "people:read"
+	ScopePeopleWrite  = "people:write"
+	ScopePeopleDelete = "people:delete" // including listing and restoration of deleted data
+)
+
+var allScopes = []string{ScopePeopleRead, ScopePeopleWrite, ScopePeopleDelete}
+
+// Roles of users authenticated by bearer tokens (JWT).
+const (
+	RoleViewer = "viewer"
+	RoleEditor = "editor"
+	RoleAdmin  = "admin"
+)
+
+var roleScopes = map[string][]string{
+	RoleViewer: {ScopePeopleRead},
+	RoleEditor: {ScopePeopleRead, ScopePeopleWrite},
+	RoleAdmin:  allScopes,
+}
+
+const (
+	apiKeyHeader = "X-API-Key"
+	apiKeyPrefix = "dmg_"
+	bearerScheme = "Bearer" // case-insensitive
+)
+
+// Authenticated client.
+type principal struct {
+	name   string // actor of changes, e.g. "api-key:reporting"
+	scopes []string
+}
+
+type principalKey struct{}
+
+func ValidScope(scope string) bool {
+	return (&principal{scopes: allScopes}).allowed(scope)
+}
+
+// Plain key must be handed over to the client, only its hash is stored.
+func NewAPIKey() (key string, hash []byte, err error) {
+	secret := make([]byte, 32)
+	_, err = rand.Read(secret)
+
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to generate API key: %w", err)
+	}
+
+	key = apiKeyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	return key, apiKeyHash(key), nil
+}
+
+func apiKeyHash(key string) []byte {
+	hash := sha256.Sum256([]byte(key))
+	return hash[:]
+}
+
+// Without authentication every client is allowed to perform all operations.
+// Bearer token (if any) takes precedence over API key.
+func (s *Service) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !s.cfg.authRequired {
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), principalKey{}, &principal{scopes: allScopes})))
+			return
+		}
+
+		var client *principal
+
+		if authorization := r.Header.Get("Authorization"); authorization != "" {
+			client = s.authenticateUser(w, authorization)
+		} else {
+			client = s.authenticateAPIKey(w, r)
+		}
+
+		if client == nil {
+			return // response is already sent
+		}
+
+		logAuthenticatedClient(r, client.name)
+
+		ctx := context.WithValue(r.Context(), principalKey{}, client)
+
+		next.ServeHTTP(w, r.WithContext(actor.NewContext(ctx, client.name)))
+	})
+}
+
+func (s *Service) authenticateAPIKey(w http.ResponseWriter, r *http.Request) *principal {
+	key := r.Header.Get(apiKeyHeader)
+
+	if key == "" {
+		respondWithUnauthorized(w, fmt.Sprintf("API key must be specified in '%s' header.", apiKeyHeader))
+		return nil
+	}
+
+	apiKey, err := s.storage.APIKey(r.Context(), apiKeyHash(key))
+
+	if err != nil {
+		log.Err(err).Msg("Failed to authenticate API key.")
+		respondWithProblem(w, http.StatusInternalServerError)
+		return nil
+	}
+
+	if apiKey == nil {
+		respondWithUnauthorized(w, "API key is invalid or revoked.")
+		return nil
+	}
+
+	return &principal{name: "api-key:" + apiKey.Name, scopes: apiKey.Scopes}
+}
+
+// Scopes of the user are granted by roles from the token claim.
+func (s *Service) authenticateUser(w http.ResponseWriter, authorization string) *principal {
+	if s.cfg.tokens == nil {
+		respondWithUnauthorized(w, "Bearer tokens are not accepted.")
+		return nil
+	}
+
+	scheme, token, _ := strings.Cut(authorization, " ")
+	token = strings.TrimSpace(token)
+
+	if !strings.EqualFold(scheme, bearerScheme) || token == "" {
+		respondWithInvalidToken(w, "Bearer token must be specified in 'Authorization' header.")
+		return nil
+	}
+
+	claims, err := s.cfg.tokens.Validate(token, time.Now())
+
+	if err != nil {
+		log.Debug().Err(err).Msg("Bearer token rejected.")
+		respondWithInvalidToken(w, "Bearer token is invalid or expired.")
+		return nil
+	}
+
+	if claims.Subject == "" {
+		respondWithInvalidToken(w, "Bearer token must identify the user ('sub' claim).")
+		return nil
+	}
+
+	return &principal{name: "user:" + claims.Subject, scopes: scopesOfRoles(claims.Strings(s.cfg.rolesClaim))}
+}
+
+func scopesOfRoles(roles []string) (scopes []string) {
+	granted := make(map[string]bool, len(allScopes))
+
+	for _, role := range roles {
+		for _, scope := range roleScopes[role] {
+			if !granted[scope] {
+				granted[scope] = true
+				scopes = append(scopes, scope)
+			}
+		}
+	}
+
+	return scopes
+}
+
+// Operation is allowed only for authenticated clients with the scope.
+func (s *Service) authorize(scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			client, _ := r.Context().Value(principalKey{}).(*principal)
+
+			if client == nil {
+				respondWithUnauthorized(w, "")
+				return
+			}
+
+			if !client.allowed(scope) {
+				respondWithProblemDetail(w, http.StatusForbidden, fmt.Sprintf("Scope '%s' is required.", scope))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Checks scope of authenticated principal from request context inside of handlers.
+func allowed(r *http.Request, scope string) bool {
+	client, _ := r.Context().Value(principalKey{}).(*principal)
+	return client != nil && client.allowed(scope)
+}
+
+func (p *principal) allowed(scope string) bool {
+	for _, s := range p.scopes {
+		if s == scope {
+			return true
+		}
+	}
+
+	return false
+}
+
+func respondWithUnauthorized(w http.ResponseWriter, detail string) {
+	w.Header().Set("WWW-Authenticate", fmt.Sprintf(`ApiKey header="%s"`, apiKeyHeader))
+	respondWithProblemDetail(w, http.StatusUnauthorized, detail)
+}
+
+func respondWithInvalidToken(w http.ResponseWriter, detail string) {
+	w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+	respondWithProblemDetail(w, http.StatusUnauthorized, detail)
+}
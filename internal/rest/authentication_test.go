@@ -0,0 +1,289 @@
+package rest
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/barpav/demography/internal/actor"
+	"github.com/barpav/demography/internal/rest/jwt"
+	"github.com/barpav/demography/internal/rest/mocks"
+	"github.com/barpav/demography/internal/rest/models"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_authenticate(t *testing.T) {
+	const key = "dmg_test"
+
+	tokens := testTokenValidator(t)
+
+	type testService struct {
+		storage Storage
+		cfg     *config
+	}
+	tests := []struct {
+		name          string
+		testService   testService
+		apiKey        string
+		authorization string
+		wantActor     string
+		wantStatus    int
+	}{
+		{
+			name: "Authenticated (200)",
+			testService: testService{
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("APIKey", mock.Anything, apiKeyHash(key)).Return(
+						&models.APIKey{Name: "reporting", Scopes: []string{ScopePeopleRead}}, nil)
+					return s
+				}(),
+				cfg: &config{authRequired: true},
+			},
+			apiKey:     key,
+			wantActor:  "api-key:reporting",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "Authentication disabled (200)",
+			testService: testService{
+				cfg: &config{},
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "No API key (401)",
+			testService: testService{
+				cfg: &config{authRequired: true},
+			},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "Unknown or revoked API key (401)",
+			testService: testService{
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("APIKey", mock.Anything, apiKeyHash(key)).Return(nil, nil)
+					return s
+				}(),
+				cfg: &config{authRequired: true},
+			},
+			apiKey:     key,
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "Storage failure (500)",
+			testService: testService{
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("APIKey", mock.Anything, apiKeyHash(key)).Return(nil, errors.New("test error"))
+					return s
+				}(),
+				cfg: &config{authRequired: true},
+			},
+			apiKey:     key,
+			wantStatus: http.StatusInternalServerError,
+		},
+		{
+			name: "Bearer token (200)",
+			testService: testService{
+				cfg: &config{authRequired: true, tokens: tokens, rolesClaim: "roles"},
+			},
+			apiKey:        key, // ignored
+			authorization: "Bearer " + testToken(t, "jdoe", time.Hour, RoleEditor),
+			wantActor:     "user:jdoe",
+			wantStatus:    http.StatusOK,
+		},
+		{
+			name: "Bearer scheme in lower case (200)",
+			testService: testService{
+				cfg: &config{authRequired: true, tokens: tokens, rolesClaim: "roles"},
+			},
+			authorization: "bearer " + testToken(t, "jdoe", time.Hour, RoleEditor),
+			wantActor:     "user:jdoe",
+			wantStatus:    http.StatusOK,
+		},
+		{
+			name: "Expired bearer token (401)",
+			testService: testService{
+				cfg: &config{authRequired: true, tokens: tokens, rolesClaim: "roles"},
+			},
+			authorization: "Bearer " + testToken(t, "jdoe", -time.Hour, RoleEditor),
+			wantStatus:    http.StatusUnauthorized,
+		},
+		{
+			name: "Bearer token without subject (401)",
+			testService: testService{
+				cfg: &config{authRequired: true, tokens: tokens, rolesClaim: "roles"},
+			},
+			authorization: "Bearer " + testToken(t, "", time.Hour, RoleEditor),
+			wantStatus:    http.StatusUnauthorized,
+		},
+		{
+			name: "Unsupported authorization scheme (401)",
+			testService: testService{
+				cfg: &config{authRequired: true, tokens: tokens, rolesClaim: "roles"},
+			},
+			authorization: "Basic amRvZTpzZWNyZXQ=",
+			wantStatus:    http.StatusUnauthorized,
+		},
+		{
+			name: "Bearer tokens not accepted (401)",
+			testService: testService{
+				cfg: &config{authRequired: true},
+			},
+			authorization: "Bearer " + testToken(t, "jdoe", time.Hour, RoleEditor),
+			wantStatus:    http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Service{
+				storage: tt.testService.storage,
+				cfg:     tt.testService.cfg,
+			}
+
+			var identified string
+			handler := s.authenticate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				identified = actor.FromContext(r.Context())
+			}))
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "/v1/people", nil)
+
+			if tt.apiKey != "" {
+				r.Header.Set(apiKeyHeader, tt.apiKey)
+			}
+
+			if tt.authorization != "" {
+				r.Header.Set("Authorization", tt.authorization)
+			}
+
+			handler.ServeHTTP(w, r)
+
+			require.Equal(t, tt.wantStatus, w.Code)
+			require.Equal(t, tt.wantActor, identified)
+
+			if tt.wantStatus == http.StatusUnauthorized {
+				require.NotEmpty(t, w.Result().Header.Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
+
+func TestService_authorize(t *testing.T) {
+	s := &Service{
+		storage: func() *mocks.Storage {
+			s := mocks.NewStorage(t)
+			s.On("APIKey", mock.Anything, apiKeyHash("dmg_reader")).Return(
+				&models.APIKey{Name: "reader", Scopes: []string{ScopePeopleRead}}, nil)
+			return s
+		}(),
+		cfg: &config{authRequired: true},
+	}
+
+	handler := s.authenticate(s.authorize(ScopePeopleDelete)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})))
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("DELETE", "/v1/people/101", nil)
+	r.Header.Set(apiKeyHeader, "dmg_reader")
+	handler.ServeHTTP(w, r)
+
+	require.Equal(t, http.StatusForbidden, w.Code)
+
+	w = httptest.NewRecorder()
+	(&Service{cfg: &config{}}).authenticate(s.authorize(ScopePeopleDelete)(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}))).ServeHTTP(w, httptest.NewRequest("DELETE", "/v1/people/101", nil))
+
+	require.Equal(t, http.StatusNoContent, w.Code)
+}
+
+func TestService_authorize_roles(t *testing.T) {
+	s := &Service{cfg: &config{authRequired: true, tokens: testTokenValidator(t), rolesClaim: "roles"}}
+
+	tests := []struct {
+		roles      []string
+		scope      string
+		wantStatus int
+	}{
+		{roles: nil, scope: ScopePeopleRead, wantStatus: http.StatusForbidden},
+		{roles: []string{"unknown"}, scope: ScopePeopleRead, wantStatus: http.StatusForbidden},
+		{roles: []string{RoleViewer}, scope: ScopePeopleRead, wantStatus: http.StatusNoContent},
+		{roles: []string{RoleViewer}, scope: ScopePeopleWrite, wantStatus: http.StatusForbidden},
+		{roles: []string{RoleEditor}, scope: ScopePeopleWrite, wantStatus: http.StatusNoContent},
+		{roles: []string{RoleEditor}, scope: ScopePeopleDelete, wantStatus: http.StatusForbidden},
+		{roles: []string{RoleViewer, RoleAdmin}, scope: ScopePeopleDelete, wantStatus: http.StatusNoContent},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%v %s (%d)", tt.roles, tt.scope, tt.wantStatus), func(t *testing.T) {
+			handler := s.authenticate(s.authorize(tt.scope)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNoContent)
+			})))
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("GET", "/v1/people", nil)
+			r.Header.Set("Authorization", "Bearer "+testToken(t, "jdoe", time.Hour, tt.roles...))
+			handler.ServeHTTP(w, r)
+
+			require.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestNewAPIKey(t *testing.T) {
+	key, hash, err := NewAPIKey()
+
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(key, apiKeyPrefix))
+	require.Equal(t, apiKeyHash(key), hash)
+
+	other, _, err := NewAPIKey()
+
+	require.NoError(t, err)
+	require.NotEqual(t, key, other)
+}
+
+func TestValidScope(t *testing.T) {
+	require.True(t, ValidScope(ScopePeopleWrite))
+	require.False(t, ValidScope("people:admin"))
+}
+
+var testTokenSecret = []byte("test-secret")
+
+func testTokenValidator(t *testing.T) *jwt.Validator {
+	keys, err := jwt.ParseKeySet([]byte(fmt.Sprintf(`{"keys": [{"kty": "oct", "k": "%s"}]}`,
+		base64.RawURLEncoding.EncodeToString(testTokenSecret))))
+	require.NoError(t, err)
+
+	return &jwt.Validator{Keys: keys}
+}
+
+// Signed with HS256, expires after ttl from now.
+func testToken(t *testing.T, subject string, ttl time.Duration, roles ...string) string {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+	payload, err := json.Marshal(map[string]any{"sub": subject, "exp": time.Now().Add(ttl).Unix(), "roles": roles})
+	require.NoError(t, err)
+
+	signed := header + "." + base64.RawURLEncoding.EncodeToString(payload)
+
+	mac := hmac.New(sha256.New, testTokenSecret)
+	mac.Write([]byte(signed))
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,181 @@
+package rest
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/barpav/demography/internal/actor"
+	"github.com/barpav/demography/internal/rest/models"
+	"github.com/rs/zerolog/log"
+)
+
+const bulkConfirmationTTL = 5 * time.Minute
+
+// Bulk operation is executed only with confirmation token, received by the same client in dry run
+// with the same parameters, and only if it affects the same number of people as the dry run.
+type bulkOperation struct {
+	name    string
+	client  string // see Service.authenticate
+	filters *models.SearchFilters
+	patch   *models.PersonDataPatchV1 // update only
+	execute func(expected int64) (affected int64, err error)
+}
+
+func (s *Service) performBulkOperationV1(w http.ResponseWriter, r *http.Request, op *bulkOperation) {
+	result := &models.BulkOperationV1{}
+	op.client = actor.FromContext(r.Context())
+	var err error
+
+	if token := r.URL.Query().Get("confirm"); token == "" {
+		result.DryRun = true
+		result.Affected, err = s.storage.CountPeople(r.Context(), op.filters)
+
+		if err == nil {
+			expires := time.Now().Add(bulkConfirmationTTL).UTC().Truncate(time.Second)
+			result.ExpiresAt = &expires
+			result.ConfirmationToken, err = s.bulkConfirmationToken(op, result.Affected, expires)
+		}
+	} else {
+		expected, valid := s.validBulkConfirmationToken(op, token)
+
+		if !valid {
+			respondWithProblemDetail(w, http.StatusBadRequest, "Confirmation token is invalid or expired.")
+			return
+		}
+
+		result.Affected, err = op.execute(expected)
+	}
+
+	if _, ok := err.(ErrPeopleAffectedChanged); ok {
+		respondWithProblemDetail(w, http.StatusConflict,
+			"Number of affected people has changed since dry run, operation must be confirmed again.")
+		return
+	}
+
+	if violation, ok := err.(ErrPersonDataConstraintViolation); ok {
+		respondWithConstraintViolation(w, violation)
+		return
+	}
+
+	if err != nil {
+		log.Err(err).Msg(fmt.Sprintf("Failed to perform bulk operation '%s' (dry run: %t).", op.name, result.DryRun))
+		respondWithProblem(w, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", models.MimeTypeBulkOperationV1)
+	err = json.NewEncoder(w).Encode(result)
+
+	if err != nil {
+		log.Err(err).Msg("Failed to serialize bulk operation result (v1).")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	if result.DryRun {
+		log.Info().Msg(fmt.Sprintf("Bulk operation '%s' dry run: %d people affected.", op.name, result.Affected))
+	} else {
+		log.Info().Msg(fmt.Sprintf("Bulk operation '%s' performed: %d people affected.", op.name, result.Affected))
+	}
+}
+
+// Unfiltered bulk operations are forbidden.
+func bulkFilters(r *http.Request) (filters *models.SearchFilters, err error) {
+	filters = &models.SearchFilters{}
+	err = readPersonFilters(r, filters)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !filters.AsOf.IsZero() {
+		return nil, errors.New("Bulk operations can be performed with current data only (parameter 'as_of' is not supported).")
+	}
+
+	if *filters == (models.SearchFilters{Translit: filters.Translit}) {
+		return nil, errors.New("At least one filter must be specified for bulk operation.")
+	}
+
+	return filters, nil
+}
+
+// Token format: <expiration unix time>.<number of affected people>.<signature>
+func (s *Service) bulkConfirmationToken(op *bulkOperation, affected int64, expires time.Time) (string, error) {
+	signature, err := s.bulkOperationSignature(op, affected, expires)
+
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%d.%d.%s", expires.Unix(), affected, signature), nil
+}
+
+// Returns number of people affected in dry run.
+func (s *Service) validBulkConfirmationToken(op *bulkOperation, token string) (affected int64, valid bool) {
+	parts := strings.SplitN(token, ".", 3)
+
+	if len(parts) != 3 {
+		return 0, false
+	}
+
+	unix, err := strconv.ParseInt(parts[0], 10, 64)
+
+	if err == nil {
+		affected, err = strconv.ParseInt(parts[1], 10, 64)
+	}
+
+	if err != nil {
+		return 0, false
+	}
+
+	expires := time.Unix(unix, 0)
+
+	if time.Now().After(expires) {
+		return 0, false
+	}
+
+	var expected string
+	expected, err = s.bulkOperationSignature(op, affected, expires)
+
+	return affected, err == nil && hmac.Equal([]byte(parts[2]), []byte(expected))
+}
+
+func (s *Service) bulkOperationSignature(op *bulkOperation, affected int64, expires time.Time) (string, error) {
+	var changes map[string]any
+
+	if op.patch != nil {
+		changes = op.patch.Changes()
+	}
+
+	parameters, err := json.Marshal(struct {
+		Operation string
+		Client    string
+		Affected  int64
+		Expires   int64
+		Filters   *models.SearchFilters
+		Changes   map[string]any
+	}{op.name, op.client, affected, expires.Unix(), op.filters, changes})
+
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize bulk operation parameters: %w", err)
+	}
+
+	mac := hmac.New(sha256.New, s.cfg.bulkTokenSecret)
+	mac.Write(parameters)
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+// Bulk operation is not performed (changes are rolled back).
+type ErrPeopleAffectedChanged interface {
+	Error() string
+	ImplementsPeopleAffectedChangedError()
+}
@@ -1,32 +1,191 @@
 package rest
 
 import (
+	"crypto/rand"
+	"fmt"
 	"os"
 	"strconv"
+	"strings"
+	"time"
+
+	"github.com/barpav/demography/internal/rest/jwt"
+	"github.com/rs/zerolog/log"
 )
 
 const (
-	defaultPort           = "8080"
-	defaultStatsTimeoutMs = 3000
+	defaultPort                   = "8080"
+	defaultStatsTimeoutMs         = 3000
+	defaultImportBatchSize        = 100
+	defaultEnrichmentConcurrency  = 5
+	defaultIdempotencyKeyTTLHours = 24
+	defaultJWTRolesClaim          = "roles"
+	defaultCORSAllowedOrigins     = "*"
+	defaultCORSAllowedHeaders     = "Accept,DNT,User-Agent,X-Requested-With,If-Modified-Since,If-Match,If-None-Match,Idempotency-Key,X-API-Key,Authorization,Cache-Control,Content-Type,Range"
+	defaultCORSMaxAgeSeconds      = 600
+	defaultReadsPerMinute         = 600
+	defaultWritesPerMinute        = 60
+	defaultAddressRequestsPerMin  = 1200
+	defaultRecordsPerMinute       = 1000
 )
 
 const (
-	envVarPort           = "DMG_HTTP_PORT"
-	envVarStatsTimeoutMs = "DMG_STATS_TIMEOUT_MS"
+	envVarPort                   = "DMG_HTTP_PORT"
+	envVarStatsTimeoutMs         = "DMG_STATS_TIMEOUT_MS"
+	envVarImportBatchSize        = "DMG_IMPORT_BATCH_SIZE"
+	envVarEnrichmentConcurrency  = "DMG_ENRICHMENT_CONCURRENCY"
+	envVarBulkTokenSecret        = "DMG_BULK_TOKEN_SECRET"
+	envVarRequireIfMatch         = "DMG_REQUIRE_IF_MATCH"
+	envVarIdempotencyKeyTTLHours = "DMG_IDEMPOTENCY_KEY_TTL_HOURS"
+	envVarAuthDisabled           = "DMG_AUTH_DISABLED"
+	envVarJWTKeysFile            = "DMG_JWT_JWKS_FILE"
+	envVarJWTIssuer              = "DMG_JWT_ISSUER"
+	envVarJWTAudience            = "DMG_JWT_AUDIENCE"
+	envVarJWTRolesClaim          = "DMG_JWT_ROLES_CLAIM"
+	envVarCORSAllowedOrigins     = "DMG_CORS_ALLOWED_ORIGINS"
+	envVarCORSAllowCredentials   = "DMG_CORS_ALLOW_CREDENTIALS"
+	envVarCORSAllowedHeaders     = "DMG_CORS_ALLOWED_HEADERS"
+	envVarCORSMaxAgeSeconds      = "DMG_CORS_MAX_AGE_SECONDS"
+	envVarReadsPerMinute         = "DMG_RATE_LIMIT_READS_PER_MINUTE"
+	envVarWritesPerMinute        = "DMG_RATE_LIMIT_WRITES_PER_MINUTE"
+	envVarAddressRequestsPerMin  = "DMG_RATE_LIMIT_ADDRESS_REQUESTS_PER_MINUTE"
+	envVarRecordsPerMinute       = "DMG_RATE_LIMIT_RECORDS_PER_MINUTE"
 )
 
 type config struct {
-	port         string
-	statsTimeout int
+	port                     string
+	statsTimeout             int
+	importBatchSize          int
+	enrichmentConcurrency    int    // max simultaneous enrichments during import and batch processing
+	bulkTokenSecret          []byte // for signing confirmation tokens of bulk operations
+	requireIfMatch           bool   // forbid unconditional changes of person data
+	idempotencyKeyTTL        time.Duration
+	authRequired             bool           // otherwise all operations are allowed for everyone
+	tokens                   *jwt.Validator // nil if bearer tokens are not accepted
+	rolesClaim               string         // e.g. "realm_access.roles"
+	cors                     corsPolicy
+	readsPerMinute           int // per client, unlimited if zero
+	writesPerMinute          int // per client, unlimited if zero
+	addressRequestsPerMinute int // per network address regardless of authentication, unlimited if zero
+	recordsPerMinute         int // per client in batches and imports, unlimited if zero
 }
 
-func (c *config) Read() {
+func (c *config) Read() error {
 	readSetting(envVarPort, defaultPort, &c.port)
 	readNumericSetting(envVarStatsTimeoutMs, defaultStatsTimeoutMs, &c.statsTimeout)
 
 	if c.statsTimeout <= 0 {
 		c.statsTimeout = defaultStatsTimeoutMs
 	}
+
+	readNumericSetting(envVarReadsPerMinute, defaultReadsPerMinute, &c.readsPerMinute)
+	readNumericSetting(envVarWritesPerMinute, defaultWritesPerMinute, &c.writesPerMinute)
+	readNumericSetting(envVarAddressRequestsPerMin, defaultAddressRequestsPerMin, &c.addressRequestsPerMinute)
+	readNumericSetting(envVarRecordsPerMinute, defaultRecordsPerMinute, &c.recordsPerMinute)
+
+	readNumericSetting(envVarImportBatchSize, defaultImportBatchSize, &c.importBatchSize)
+
+	if c.importBatchSize <= 0 {
+		c.importBatchSize = defaultImportBatchSize
+	}
+
+	if c.recordsPerMinute > 0 && c.importBatchSize > c.recordsPerMinute {
+		c.importBatchSize = c.recordsPerMinute // otherwise no batch of import is allowed
+	}
+
+	readNumericSetting(envVarEnrichmentConcurrency, defaultEnrichmentConcurrency, &c.enrichmentConcurrency)
+
+	if c.enrichmentConcurrency <= 0 {
+		c.enrichmentConcurrency = defaultEnrichmentConcurrency
+	}
+
+	var ttlHours int
+	readNumericSetting(envVarIdempotencyKeyTTLHours, defaultIdempotencyKeyTTLHours, &ttlHours)
+
+	if ttlHours <= 0 {
+		ttlHours = defaultIdempotencyKeyTTLHours
+	}
+
+	c.idempotencyKeyTTL = time.Duration(ttlHours) * time.Hour
+
+	c.requireIfMatch, _ = strconv.ParseBool(os.Getenv(envVarRequireIfMatch))
+
+	authDisabled, _ := strconv.ParseBool(os.Getenv(envVarAuthDisabled))
+	c.authRequired = !authDisabled
+
+	err := c.readTokenSettings()
+
+	if err != nil {
+		return err
+	}
+
+	err = c.readCORSSettings()
+
+	if err != nil {
+		return err
+	}
+
+	c.bulkTokenSecret = []byte(os.Getenv(envVarBulkTokenSecret))
+
+	if len(c.bulkTokenSecret) == 0 {
+		// confirmation tokens become invalid after restart
+		c.bulkTokenSecret = make([]byte, 32)
+		_, err := rand.Read(c.bulkTokenSecret)
+
+		if err != nil {
+			log.Err(err).Msg("Failed to generate secret for bulk operations confirmation tokens.")
+		}
+	}
+
+	return nil
+}
+
+// Bearer tokens are accepted only if verification keys are loaded.
+func (c *config) readTokenSettings() (err error) {
+	keysFile := os.Getenv(envVarJWTKeysFile)
+
+	if keysFile == "" {
+		return nil
+	}
+
+	c.tokens = &jwt.Validator{
+		Issuer:   os.Getenv(envVarJWTIssuer),
+		Audience: os.Getenv(envVarJWTAudience),
+	}
+
+	readSetting(envVarJWTRolesClaim, defaultJWTRolesClaim, &c.rolesClaim)
+
+	c.tokens.Keys, err = jwt.ReadKeySet(keysFile)
+
+	if err != nil {
+		return fmt.Errorf("failed to load JWT verification keys: %w", err)
+	}
+
+	return nil
+}
+
+// Browsers don't send credentials to any origin ("*"), so such settings are considered misconfiguration.
+func (c *config) readCORSSettings() error {
+	var origins, headers string
+	readSetting(envVarCORSAllowedOrigins, defaultCORSAllowedOrigins, &origins)
+	readSetting(envVarCORSAllowedHeaders, defaultCORSAllowedHeaders, &headers)
+
+	c.cors.origins = parseList(origins, true)
+	c.cors.allowedHeaders = strings.Join(parseList(headers, false), ",")
+	c.cors.allowCredentials, _ = strconv.ParseBool(os.Getenv(envVarCORSAllowCredentials))
+
+	readNumericSetting(envVarCORSMaxAgeSeconds, defaultCORSMaxAgeSeconds, &c.cors.maxAge)
+
+	if c.cors.maxAge < 0 {
+		c.cors.maxAge = defaultCORSMaxAgeSeconds
+	}
+
+	for _, origin := range c.cors.origins {
+		if origin == "*" && c.cors.allowCredentials {
+			return fmt.Errorf("credentials can't be allowed for any CORS origin, origins must be listed in %s", envVarCORSAllowedOrigins)
+		}
+	}
+
+	return nil
 }
 
 func readSetting(setting, defaultValue string, result *string) {
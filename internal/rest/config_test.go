@@ -0,0 +1,50 @@
+package rest
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_readTokenSettings(t *testing.T) {
+	t.Setenv(envVarJWTKeysFile, filepath.Join(t.TempDir(), "missing.json"))
+
+	c := &config{}
+	require.Error(t, c.Read())
+}
+
+func TestConfig_readCORSSettings(t *testing.T) {
+	tests := []struct {
+		name             string
+		origins          string
+		allowCredentials string
+		wantErr          bool
+	}{
+		{
+			name:    "Any origin",
+			origins: "*",
+		},
+		{
+			name:             "Credentials for listed origins",
+			origins:          "https://barpav.github.io,https://*.example.com",
+			allowCredentials: "true",
+		},
+		{
+			name:             "Credentials for any origin",
+			origins:          "https://barpav.github.io,*",
+			allowCredentials: "true",
+			wantErr:          true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(envVarCORSAllowedOrigins, tt.origins)
+			t.Setenv(envVarCORSAllowCredentials, tt.allowCredentials)
+
+			c := &config{}
+			require.Equal(t, tt.wantErr, c.readCORSSettings() != nil)
+		})
+	}
+}
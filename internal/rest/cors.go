@@ -0,0 +1,114 @@
+package rest
+
+import (
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+)
+
+const (
+	corsAllowedMethods = "POST, GET, HEAD, PUT, PATCH, DELETE, OPTIONS"
+	corsExposedHeaders = "Content-Type,Content-Length,Content-Range,Content-Disposition,ETag,Idempotent-Replayed,Retry-After,RateLimit-Limit,RateLimit-Remaining,RateLimit-Reset,RateLimit-Policy"
+)
+
+// Cross-origin resource sharing (https://fetch.spec.whatwg.org/#http-cors-protocol).
+// Normally done by front end web server.
+type corsPolicy struct {
+	origins          []string // exact origins or patterns like "https://*.example.com", "*" allows any
+	allowCredentials bool
+	allowedHeaders   string
+	maxAge           int // seconds, preflight responses are not cached if zero
+}
+
+func (p *corsPolicy) handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+
+		if p.varies() {
+			w.Header().Add("Vary", "Origin")
+		}
+
+		if preflight {
+			w.Header().Add("Vary", "Access-Control-Request-Method")
+			w.Header().Add("Vary", "Access-Control-Request-Headers")
+		}
+
+		if origin == "" || !p.allowed(origin) {
+			if preflight {
+				w.WriteHeader(http.StatusNoContent) // without permission for the browser
+				return
+			}
+
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if p.varies() {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+		} else {
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+		}
+
+		if p.allowCredentials {
+			w.Header().Set("Access-Control-Allow-Credentials", "true")
+		}
+
+		if preflight {
+			w.Header().Set("Access-Control-Allow-Methods", corsAllowedMethods)
+			w.Header().Set("Access-Control-Allow-Headers", p.allowedHeaders)
+
+			if p.maxAge > 0 {
+				w.Header().Set("Access-Control-Max-Age", strconv.Itoa(p.maxAge))
+			}
+
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		w.Header().Set("Access-Control-Expose-Headers", corsExposedHeaders)
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (p *corsPolicy) allowed(origin string) bool {
+	origin = strings.ToLower(origin)
+
+	for _, allowed := range p.origins {
+		if allowed == "*" || allowed == origin {
+			return true
+		}
+
+		if matched, _ := path.Match(allowed, origin); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Wildcard can't be used with credentials, so allowed origin is echoed instead.
+func (p *corsPolicy) varies() bool {
+	return p.allowCredentials || len(p.origins) != 1 || p.origins[0] != "*"
+}
+
+// Comma-separated list without empty values.
+func parseList(list string, lowerCase bool) (values []string) {
+	for _, v := range strings.Split(list, ",") {
+		v = strings.TrimSpace(v)
+
+		if v == "" {
+			continue
+		}
+
+		if lowerCase {
+			v = strings.ToLower(v)
+		}
+
+		values = append(values, v)
+	}
+
+	return values
+}
@@ -0,0 +1,140 @@
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCorsPolicy_handler(t *testing.T) {
+	restricted := &corsPolicy{
+		origins:          []string{"https://app.example.com", "https://*.example.org"},
+		allowCredentials: true,
+		allowedHeaders:   "Content-Type,Authorization",
+		maxAge:           600,
+	}
+
+	tests := []struct {
+		name            string
+		policy          *corsPolicy
+		method          string
+		origin          string
+		preflight       bool
+		wantStatus      int
+		wantAllowOrigin string
+		wantVary        []string
+		wantHeaders     map[string]string
+	}{
+		{
+			name:            "Any origin",
+			policy:          &corsPolicy{origins: []string{"*"}},
+			method:          "GET",
+			origin:          "https://app.example.com",
+			wantStatus:      http.StatusOK,
+			wantAllowOrigin: "*",
+			wantHeaders:     map[string]string{"Access-Control-Expose-Headers": corsExposedHeaders},
+		},
+		{
+			name:            "Any origin preflight",
+			policy:          &corsPolicy{origins: []string{"*"}, allowedHeaders: "Content-Type"},
+			method:          "OPTIONS",
+			origin:          "https://app.example.com",
+			preflight:       true,
+			wantStatus:      http.StatusNoContent,
+			wantAllowOrigin: "*",
+			wantVary:        []string{"Access-Control-Request-Method", "Access-Control-Request-Headers"},
+			wantHeaders: map[string]string{
+				"Access-Control-Allow-Methods": corsAllowedMethods,
+				"Access-Control-Allow-Headers": "Content-Type",
+				"Access-Control-Max-Age":       "",
+			},
+		},
+		{
+			name:            "Exact origin",
+			policy:          restricted,
+			method:          "POST",
+			origin:          "https://app.example.com",
+			wantStatus:      http.StatusOK,
+			wantAllowOrigin: "https://app.example.com",
+			wantVary:        []string{"Origin"},
+			wantHeaders:     map[string]string{"Access-Control-Allow-Credentials": "true"},
+		},
+		{
+			name:            "Origin pattern",
+			policy:          restricted,
+			method:          "OPTIONS",
+			origin:          "https://Reports.Example.org",
+			preflight:       true,
+			wantStatus:      http.StatusNoContent,
+			wantAllowOrigin: "https://Reports.Example.org",
+			wantVary:        []string{"Origin", "Access-Control-Request-Method", "Access-Control-Request-Headers"},
+			wantHeaders: map[string]string{
+				"Access-Control-Allow-Credentials": "true",
+				"Access-Control-Allow-Headers":     "Content-Type,Authorization",
+				"Access-Control-Max-Age":           "600",
+			},
+		},
+		{
+			name:       "Origin not allowed",
+			policy:     restricted,
+			method:     "GET",
+			origin:     "https://example.org",
+			wantStatus: http.StatusOK,
+			wantVary:   []string{"Origin"},
+		},
+		{
+			name:       "Preflight of origin not allowed",
+			policy:     restricted,
+			method:     "OPTIONS",
+			origin:     "https://evil.example.com",
+			preflight:  true,
+			wantStatus: http.StatusNoContent,
+			wantVary:   []string{"Origin", "Access-Control-Request-Method", "Access-Control-Request-Headers"},
+			wantHeaders: map[string]string{
+				"Access-Control-Allow-Methods": "",
+			},
+		},
+		{
+			name:       "Same origin",
+			policy:     restricted,
+			method:     "GET",
+			wantStatus: http.StatusOK,
+			wantVary:   []string{"Origin"},
+		},
+		{
+			name:       "Not preflight",
+			policy:     &corsPolicy{origins: []string{"*"}},
+			method:     "OPTIONS",
+			wantStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := tt.policy.handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(tt.method, "/v1/people", nil)
+
+			if tt.origin != "" {
+				r.Header.Set("Origin", tt.origin)
+			}
+
+			if tt.preflight {
+				r.Header.Set("Access-Control-Request-Method", "DELETE")
+			}
+
+			handler.ServeHTTP(w, r)
+
+			require.Equal(t, tt.wantStatus, w.Code)
+			require.Equal(t, tt.wantAllowOrigin, w.Result().Header.Get("Access-Control-Allow-Origin"))
+			require.Equal(t, tt.wantVary, w.Result().Header.Values("Vary"))
+
+			for header, value := range tt.wantHeaders {
+				require.Equal(t, value, w.Result().Header.Get(header), header)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/barpav/demography/internal/rest/models"
+	"github.com/barpav/demography/internal/rest/negotiation"
+)
+
+// https://barpav.github.io/demography-api/#/people/delete_people
+func (s *Service) deletePeople(w http.ResponseWriter, r *http.Request) {
+	switch negotiation.Accept(r.Header.Get("Accept"), models.MimeTypeBulkOperationV1) {
+	case models.MimeTypeBulkOperationV1:
+		s.deletePeopleV1(w, r)
+	default:
+		respondWithProblem(w, http.StatusNotAcceptable)
+		return
+	}
+}
+
+func (s *Service) deletePeopleV1(w http.ResponseWriter, r *http.Request) {
+	filters, err := bulkFilters(r)
+
+	if err != nil {
+		respondWithInvalidRequest(w, err)
+		return
+	}
+
+	s.performBulkOperationV1(w, r, &bulkOperation{
+		name:    "delete",
+		filters: filters,
+		execute: func(expected int64) (int64, error) {
+			return s.storage.DeletePeople(r.Context(), filters, expected)
+		},
+	})
+}
@@ -0,0 +1,197 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/barpav/demography/internal/rest/mocks"
+	"github.com/barpav/demography/internal/rest/models"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_deletePeople(t *testing.T) {
+	cfg := &config{bulkTokenSecret: []byte("test")}
+	token := func(client string, filters *models.SearchFilters, affected int64, expires time.Time) string {
+		s := &Service{cfg: cfg}
+		token, err := s.bulkConfirmationToken(&bulkOperation{name: "delete", client: client, filters: filters}, affected, expires)
+		require.NoError(t, err)
+		return url.QueryEscape(token)
+	}
+
+	type testService struct {
+		storage Storage
+	}
+	type args struct {
+		w *httptest.ResponseRecorder
+		r *http.Request
+	}
+	tests := []struct {
+		name        string
+		testService testService
+		args        args
+		wantHeaders map[string]string
+		wantDryRun  bool
+		wantCount   int64
+		wantStatus  int
+	}{
+		{
+			name: "Dry run (200)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("DELETE", "/v1/people?surname=Ivanov", nil),
+			},
+			testService: testService{
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("CountPeople", mock.Anything, &models.SearchFilters{Surname: "Ivanov"}).Return(int64(7), nil)
+					return s
+				}(),
+			},
+			wantHeaders: map[string]string{
+				"Content-Type": models.MimeTypeBulkOperationV1,
+			},
+			wantDryRun: true,
+			wantCount:  7,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "Confirmed (200)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("DELETE", "/v1/people?surname=Ivanov&confirm="+
+					token("", &models.SearchFilters{Surname: "Ivanov"}, 6, time.Now().Add(time.Minute)), nil),
+			},
+			testService: testService{
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("DeletePeople", mock.Anything, &models.SearchFilters{Surname: "Ivanov"}, int64(6)).Return(int64(6), nil)
+					return s
+				}(),
+			},
+			wantHeaders: map[string]string{
+				"Content-Type": models.MimeTypeBulkOperationV1,
+			},
+			wantCount:  6,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "Affected people changed since dry run (409)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("DELETE", "/v1/people?surname=Ivanov&confirm="+
+					token("", &models.SearchFilters{Surname: "Ivanov"}, 3, time.Now().Add(time.Minute)), nil),
+			},
+			testService: testService{
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("DeletePeople", mock.Anything, &models.SearchFilters{Surname: "Ivanov"}, int64(3)).
+						Return(int64(0), ErrPeopleAffectedChangedTest{})
+					return s
+				}(),
+			},
+			wantHeaders: map[string]string{},
+			wantStatus:  http.StatusConflict,
+		},
+		{
+			name: "Token of other client (400)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("DELETE", "/v1/people?surname=Ivanov&confirm="+
+					token("api-key:reporting", &models.SearchFilters{Surname: "Ivanov"}, 6, time.Now().Add(time.Minute)), nil),
+			},
+			wantHeaders: map[string]string{},
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			name: "Token with other number of affected people (400)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("DELETE", "/v1/people?surname=Ivanov&confirm="+strings.Replace(
+					token("", &models.SearchFilters{Surname: "Ivanov"}, 6, time.Now().Add(time.Minute)), ".6.", ".3000.", 1), nil),
+			},
+			wantHeaders: map[string]string{},
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			name: "Token for other filters (400)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("DELETE", "/v1/people?surname=Petrov&confirm="+
+					token("", &models.SearchFilters{Surname: "Ivanov"}, 6, time.Now().Add(time.Minute)), nil),
+			},
+			wantHeaders: map[string]string{},
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			name: "Expired token (400)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("DELETE", "/v1/people?surname=Ivanov&confirm="+
+					token("", &models.SearchFilters{Surname: "Ivanov"}, 6, time.Now().Add(-time.Minute)), nil),
+			},
+			wantHeaders: map[string]string{},
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			name: "Point-in-time filter (400)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("DELETE", "/v1/people?surname=Ivanov&as_of=2023-09-01", nil),
+			},
+			wantHeaders: map[string]string{},
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			name: "No filters (400)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("DELETE", "/v1/people?translit=true", nil),
+			},
+			wantHeaders: map[string]string{},
+			wantStatus:  http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Service{
+				cfg:     cfg,
+				storage: tt.testService.storage,
+			}
+			s.deletePeople(tt.args.w, tt.args.r)
+
+			for k, v := range tt.wantHeaders {
+				require.Equal(t, v, tt.args.w.Result().Header.Get(k))
+			}
+
+			require.Equal(t, tt.wantStatus, tt.args.w.Code)
+
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			body := models.BulkOperationV1{}
+			err := json.NewDecoder(tt.args.w.Body).Decode(&body)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantDryRun, body.DryRun)
+			require.Equal(t, tt.wantCount, body.Affected)
+			require.Equal(t, tt.wantDryRun, body.ConfirmationToken != "")
+			require.Equal(t, tt.wantDryRun, body.ExpiresAt != nil)
+		})
+	}
+}
+
+type ErrPeopleAffectedChangedTest struct{}
+
+func (e ErrPeopleAffectedChangedTest) Error() string {
+	return "number of affected people has changed (test)"
+}
+
+func (e ErrPeopleAffectedChangedTest) ImplementsPeopleAffectedChangedError() {
+}
@@ -14,20 +14,31 @@ func (s *Service) deletePersonData(w http.ResponseWriter, r *http.Request) {
 	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 0)
 
 	if err != nil {
-		w.WriteHeader(http.StatusNotFound)
+		respondWithProblem(w, http.StatusNotFound)
 		return
 	}
 
-	err = s.storage.DeletePersonData(r.Context(), id)
+	versions, ok := s.expectedVersions(w, r)
+
+	if !ok {
+		return
+	}
+
+	err = s.storage.DeletePersonData(r.Context(), id, versions)
 
 	if err != nil {
 		if _, ok := err.(ErrPersonDataNotFound); ok {
-			w.WriteHeader(http.StatusNotFound)
+			respondWithProblem(w, http.StatusNotFound)
+			return
+		}
+
+		if _, ok := err.(ErrPersonDataVersionMismatch); ok {
+			respondWithProblemDetail(w, http.StatusPreconditionFailed, "Person data has been changed since ETag was received.")
 			return
 		}
 
 		log.Err(err).Msg("Failed to delete person data.")
-		w.WriteHeader(http.StatusInternalServerError)
+		respondWithProblem(w, http.StatusInternalServerError)
 		return
 	}
 
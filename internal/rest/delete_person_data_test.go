@@ -15,6 +15,7 @@ import (
 func TestService_deletePersonData(t *testing.T) {
 	type testService struct {
 		storage Storage
+		cfg     *config
 	}
 	type args struct {
 		w *httptest.ResponseRecorder
@@ -40,9 +41,10 @@ func TestService_deletePersonData(t *testing.T) {
 			testService: testService{
 				storage: func() *mocks.Storage {
 					s := mocks.NewStorage(t)
-					s.On("DeletePersonData", mock.Anything, int64(101)).Return(nil)
+					s.On("DeletePersonData", mock.Anything, int64(101), []int(nil)).Return(nil)
 					return s
 				}(),
+				cfg: &config{},
 			},
 			wantStatus: http.StatusNoContent,
 		},
@@ -60,12 +62,112 @@ func TestService_deletePersonData(t *testing.T) {
 			testService: testService{
 				storage: func() *mocks.Storage {
 					s := mocks.NewStorage(t)
-					s.On("DeletePersonData", mock.Anything, int64(101)).Return(ErrPersonDataNotFoundTest{})
+					s.On("DeletePersonData", mock.Anything, int64(101), []int(nil)).Return(ErrPersonDataNotFoundTest{})
 					return s
 				}(),
+				cfg: &config{},
 			},
 			wantStatus: http.StatusNotFound,
 		},
+		{
+			name: "Deleted with matching ETag (204)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					r := httptest.NewRequest("DELETE", "/v1/people/{id}", nil)
+					r.Header.Set("If-Match", `"3"`)
+					ctx := chi.NewRouteContext()
+					ctx.URLParams.Add("id", "101")
+					return r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, ctx))
+				}(),
+			},
+			testService: testService{
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("DeletePersonData", mock.Anything, int64(101), []int{3}).Return(nil)
+					return s
+				}(),
+				cfg: &config{requireIfMatch: true},
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name: "Deleted with one of listed ETags (204)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					r := httptest.NewRequest("DELETE", "/v1/people/{id}", nil)
+					r.Header.Set("If-Match", `"2", W/"5", "3"`)
+					ctx := chi.NewRouteContext()
+					ctx.URLParams.Add("id", "101")
+					return r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, ctx))
+				}(),
+			},
+			testService: testService{
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("DeletePersonData", mock.Anything, int64(101), []int{2, 3}).Return(nil)
+					return s
+				}(),
+				cfg: &config{},
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name: "Person data version mismatch (412)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					r := httptest.NewRequest("DELETE", "/v1/people/{id}", nil)
+					r.Header.Set("If-Match", `"3"`)
+					ctx := chi.NewRouteContext()
+					ctx.URLParams.Add("id", "101")
+					return r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, ctx))
+				}(),
+			},
+			testService: testService{
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("DeletePersonData", mock.Anything, int64(101), []int{3}).Return(ErrPersonDataVersionMismatchTest{})
+					return s
+				}(),
+				cfg: &config{},
+			},
+			wantStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name: "Weak ETag never matches (412)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					r := httptest.NewRequest("DELETE", "/v1/people/{id}", nil)
+					r.Header.Set("If-Match", `W/"3"`)
+					ctx := chi.NewRouteContext()
+					ctx.URLParams.Add("id", "101")
+					return r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, ctx))
+				}(),
+			},
+			testService: testService{
+				cfg: &config{},
+			},
+			wantStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name: "If-Match is required (428)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					r := httptest.NewRequest("DELETE", "/v1/people/{id}", nil)
+					ctx := chi.NewRouteContext()
+					ctx.URLParams.Add("id", "101")
+					return r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, ctx))
+				}(),
+			},
+			testService: testService{
+				cfg: &config{requireIfMatch: true},
+			},
+			wantStatus: http.StatusPreconditionRequired,
+		},
 		{
 			name: "Person not found - bad id (404)",
 			args: args{
@@ -85,6 +187,7 @@ func TestService_deletePersonData(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			s := &Service{
 				storage: tt.testService.storage,
+				cfg:     tt.testService.cfg,
 			}
 			s.deletePersonData(tt.args.w, tt.args.r)
 
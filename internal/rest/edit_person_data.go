@@ -6,17 +6,20 @@ import (
 	"strconv"
 
 	"github.com/barpav/demography/internal/rest/models"
+	"github.com/barpav/demography/internal/rest/negotiation"
 	"github.com/go-chi/chi/v5"
 	"github.com/rs/zerolog/log"
 )
 
 // https://barpav.github.io/demography-api/#/people/put_people__id_
 func (s *Service) editPersonData(w http.ResponseWriter, r *http.Request) {
-	switch r.Header.Get("Content-Type") {
+	switch negotiation.ContentType(r.Header.Get("Content-Type"), models.MimeTypeEditedPersonDataV1, models.MimeTypeEditedPersonDataV2) {
 	case models.MimeTypeEditedPersonDataV1:
 		s.editPersonDataV1(w, r)
+	case models.MimeTypeEditedPersonDataV2:
+		s.editPersonDataV2(w, r)
 	default:
-		w.WriteHeader(http.StatusUnsupportedMediaType)
+		respondWithProblem(w, http.StatusUnsupportedMediaType)
 		return
 	}
 }
@@ -25,7 +28,13 @@ func (s *Service) editPersonDataV1(w http.ResponseWriter, r *http.Request) {
 	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 0)
 
 	if err != nil {
-		w.WriteHeader(http.StatusNotFound)
+		respondWithProblem(w, http.StatusNotFound)
+		return
+	}
+
+	versions, ok := s.expectedVersions(w, r)
+
+	if !ok {
 		return
 	}
 
@@ -33,27 +42,95 @@ func (s *Service) editPersonDataV1(w http.ResponseWriter, r *http.Request) {
 	err = editedData.Deserialize(r.Body)
 
 	if err != nil {
-		http.Error(w, err.Error(), 400)
+		respondWithInvalidRequest(w, err)
 		return
 	}
 
-	err = s.storage.UpdatePersonDataV1(r.Context(), id, &editedData)
+	version, err := s.storage.UpdatePersonDataV1(r.Context(), id, &editedData, versions)
 
 	if err != nil {
 		if _, ok := err.(ErrPersonDataNotFound); ok {
-			w.WriteHeader(http.StatusNotFound)
+			respondWithProblem(w, http.StatusNotFound)
+			return
+		}
+
+		if _, ok := err.(ErrPersonDataVersionMismatch); ok {
+			respondWithProblemDetail(w, http.StatusPreconditionFailed, "Person data has been changed since ETag was received.")
+			return
+		}
+
+		if violation, ok := err.(ErrPersonDataConstraintViolation); ok {
+			respondWithConstraintViolation(w, violation)
 			return
 		}
 
 		log.Err(err).Msg("Failed to update person data (v1).")
-		w.WriteHeader(http.StatusInternalServerError)
+		respondWithProblem(w, http.StatusInternalServerError)
 		return
 	}
 
+	w.Header().Set("ETag", entityTag(version))
+
 	log.Info().Msg(fmt.Sprintf("Person data with id '%d' edited.", id))
 }
 
+func (s *Service) editPersonDataV2(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 0)
+
+	if err != nil {
+		respondWithProblem(w, http.StatusNotFound)
+		return
+	}
+
+	versions, ok := s.expectedVersions(w, r)
+
+	if !ok {
+		return
+	}
+
+	editedData := models.EditedPersonDataV2{}
+	err = editedData.Deserialize(r.Body)
+
+	if err != nil {
+		respondWithInvalidRequest(w, err)
+		return
+	}
+
+	version, err := s.storage.UpdatePersonDataV2(r.Context(), id, &editedData, versions)
+
+	if err != nil {
+		if _, ok := err.(ErrPersonDataNotFound); ok {
+			respondWithProblem(w, http.StatusNotFound)
+			return
+		}
+
+		if _, ok := err.(ErrPersonDataVersionMismatch); ok {
+			respondWithProblemDetail(w, http.StatusPreconditionFailed, "Person data has been changed since ETag was received.")
+			return
+		}
+
+		if violation, ok := err.(ErrPersonDataConstraintViolation); ok {
+			respondWithConstraintViolation(w, violation)
+			return
+		}
+
+		log.Err(err).Msg("Failed to update person data (v2).")
+		respondWithProblem(w, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("ETag", entityTag(version))
+
+	log.Info().Msg(fmt.Sprintf("Person data with id '%d' edited (v2).", id))
+}
+
 type ErrPersonDataNotFound interface {
 	Error() string
 	ImplementsPersonDataNotFoundError()
 }
+
+type ErrPersonDataConstraintViolation interface {
+	Error() string
+	ImplementsPersonDataConstraintViolationError()
+	InvalidField() (name, reason string)
+}
@@ -7,7 +7,9 @@ import (
 	"log"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/barpav/demography/internal/rest/mocks"
 	"github.com/barpav/demography/internal/rest/models"
@@ -19,6 +21,7 @@ import (
 func TestService_editPersonData(t *testing.T) {
 	type testService struct {
 		storage Storage
+		cfg     *config
 	}
 	type args struct {
 		w *httptest.ResponseRecorder
@@ -28,6 +31,7 @@ func TestService_editPersonData(t *testing.T) {
 		name        string
 		testService testService
 		args        args
+		wantHeaders map[string]string
 		wantStatus  int
 	}{
 		{
@@ -60,10 +64,55 @@ func TestService_editPersonData(t *testing.T) {
 							Name:       "Ivan",
 							Patronymic: "Ivanovich",
 							Surname:    "Ivanov",
-						},
-					).Return(nil)
+						}, []int(nil),
+					).Return(2, nil)
 					return s
 				}(),
+				cfg: &config{},
+			},
+			wantHeaders: map[string]string{
+				"ETag": `"2"`,
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "Content type with parameters (200)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					m := models.EditedPersonDataV1{
+						Name:       "Ivan",
+						Patronymic: "Ivanovich",
+						Surname:    "Ivanov",
+					}
+					var buf bytes.Buffer
+					err := json.NewEncoder(&buf).Encode(m)
+					if err != nil {
+						log.Fatal(err)
+					}
+					r := httptest.NewRequest("PUT", "/v1/people/{id}", &buf)
+					r.Header.Set("Content-Type", models.MimeTypeEditedPersonDataV1+"; charset=utf-8")
+					ctx := chi.NewRouteContext()
+					ctx.URLParams.Add("id", "101")
+					return r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, ctx))
+				}(),
+			},
+			testService: testService{
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("UpdatePersonDataV1", mock.Anything, int64(101),
+						&models.EditedPersonDataV1{
+							Name:       "Ivan",
+							Patronymic: "Ivanovich",
+							Surname:    "Ivanov",
+						}, []int(nil),
+					).Return(2, nil)
+					return s
+				}(),
+				cfg: &config{},
+			},
+			wantHeaders: map[string]string{
+				"ETag": `"2"`,
 			},
 			wantStatus: http.StatusOK,
 		},
@@ -88,6 +137,110 @@ func TestService_editPersonData(t *testing.T) {
 					return r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, ctx))
 				}(),
 			},
+			testService: testService{
+				cfg: &config{},
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Incorrect country code (400)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					m := models.EditedPersonDataV1{
+						Name:    "Ivan",
+						Surname: "Ivanov",
+						Country: "RUS",
+					}
+					var buf bytes.Buffer
+					err := json.NewEncoder(&buf).Encode(m)
+					if err != nil {
+						log.Fatal(err)
+					}
+					r := httptest.NewRequest("PUT", "/v1/people/{id}", &buf)
+					r.Header.Set("Content-Type", models.MimeTypeEditedPersonDataV1)
+					ctx := chi.NewRouteContext()
+					ctx.URLParams.Add("id", "101")
+					return r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, ctx))
+				}(),
+			},
+			testService: testService{
+				cfg: &config{},
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Country code case normalized (200)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					m := models.EditedPersonDataV1{
+						Name:    "Ivan",
+						Surname: "Ivanov",
+						Country: "ru",
+					}
+					var buf bytes.Buffer
+					err := json.NewEncoder(&buf).Encode(m)
+					if err != nil {
+						log.Fatal(err)
+					}
+					r := httptest.NewRequest("PUT", "/v1/people/{id}", &buf)
+					r.Header.Set("Content-Type", models.MimeTypeEditedPersonDataV1)
+					ctx := chi.NewRouteContext()
+					ctx.URLParams.Add("id", "101")
+					return r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, ctx))
+				}(),
+			},
+			testService: testService{
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("UpdatePersonDataV1", mock.Anything, int64(101),
+						&models.EditedPersonDataV1{
+							Name:    "Ivan",
+							Surname: "Ivanov",
+							Country: "RU",
+						}, []int(nil),
+					).Return(2, nil)
+					return s
+				}(),
+				cfg: &config{},
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "Negative age (400)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					r := httptest.NewRequest("PUT", "/v1/people/{id}",
+						strings.NewReader(`{"name": "Ivan", "surname": "Ivanov", "age": -1}`))
+					r.Header.Set("Content-Type", models.MimeTypeEditedPersonDataV1)
+					ctx := chi.NewRouteContext()
+					ctx.URLParams.Add("id", "101")
+					return r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, ctx))
+				}(),
+			},
+			testService: testService{
+				cfg: &config{},
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Unrealistic age (400)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					r := httptest.NewRequest("PUT", "/v1/people/{id}",
+						strings.NewReader(`{"name": "Ivan", "surname": "Ivanov", "age": 40000}`))
+					r.Header.Set("Content-Type", models.MimeTypeEditedPersonDataV1)
+					ctx := chi.NewRouteContext()
+					ctx.URLParams.Add("id", "101")
+					return r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, ctx))
+				}(),
+			},
+			testService: testService{
+				cfg: &config{},
+			},
 			wantStatus: http.StatusBadRequest,
 		},
 		{
@@ -108,6 +261,9 @@ func TestService_editPersonData(t *testing.T) {
 					return r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, ctx))
 				}(),
 			},
+			testService: testService{
+				cfg: &config{},
+			},
 			wantStatus: http.StatusBadRequest,
 		},
 		{
@@ -140,13 +296,139 @@ func TestService_editPersonData(t *testing.T) {
 							Name:       "Ivan",
 							Patronymic: "Ivanovich",
 							Surname:    "Ivanov",
-						},
-					).Return(ErrPersonDataNotFoundTest{})
+						}, []int(nil),
+					).Return(0, ErrPersonDataNotFoundTest{})
 					return s
 				}(),
+				cfg: &config{},
 			},
 			wantStatus: http.StatusNotFound,
 		},
+		{
+			name: "Person data version mismatch (412)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					m := models.EditedPersonDataV1{
+						Name:       "Ivan",
+						Patronymic: "Ivanovich",
+						Surname:    "Ivanov",
+					}
+					var buf bytes.Buffer
+					err := json.NewEncoder(&buf).Encode(m)
+					if err != nil {
+						log.Fatal(err)
+					}
+					r := httptest.NewRequest("PUT", "/v1/people/{id}", &buf)
+					r.Header.Set("Content-Type", models.MimeTypeEditedPersonDataV1)
+					r.Header.Set("If-Match", `"1"`)
+					ctx := chi.NewRouteContext()
+					ctx.URLParams.Add("id", "101")
+					return r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, ctx))
+				}(),
+			},
+			testService: testService{
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("UpdatePersonDataV1", mock.Anything, int64(101), mock.Anything, []int{1}).
+						Return(0, ErrPersonDataVersionMismatchTest{})
+					return s
+				}(),
+				cfg: &config{},
+			},
+			wantStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name: "Data constraint violation (422)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					m := models.EditedPersonDataV1{
+						Name:    "Ivan",
+						Surname: "Ivanov",
+						Age:     42,
+					}
+					var buf bytes.Buffer
+					err := json.NewEncoder(&buf).Encode(m)
+					if err != nil {
+						log.Fatal(err)
+					}
+					r := httptest.NewRequest("PUT", "/v1/people/{id}", &buf)
+					r.Header.Set("Content-Type", models.MimeTypeEditedPersonDataV1)
+					ctx := chi.NewRouteContext()
+					ctx.URLParams.Add("id", "101")
+					return r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, ctx))
+				}(),
+			},
+			testService: testService{
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("UpdatePersonDataV1", mock.Anything, int64(101), mock.Anything, []int(nil)).
+						Return(0, ErrPersonDataConstraintViolationTest{})
+					return s
+				}(),
+				cfg: &config{},
+			},
+			wantHeaders: map[string]string{
+				"Content-Type": models.MimeTypeProblem,
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "Edited (v2), age derived from birth date (200)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					body := `{"surname": "Ivanov", "name": "Ivan", "birthDate": "1990-05-01", "gender": "male", "country": "ru"}`
+					r := httptest.NewRequest("PUT", "/v1/people/{id}", strings.NewReader(body))
+					r.Header.Set("Content-Type", models.MimeTypeEditedPersonDataV2)
+					ctx := chi.NewRouteContext()
+					ctx.URLParams.Add("id", "101")
+					return r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, ctx))
+				}(),
+			},
+			testService: testService{
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("UpdatePersonDataV2", mock.Anything, int64(101),
+						&models.EditedPersonDataV2{
+							NewPersonDataV2: models.NewPersonDataV2{
+								Surname:   "Ivanov",
+								Name:      "Ivan",
+								BirthDate: "1990-05-01",
+								Gender:    "male",
+							},
+							Age:     models.AgeAt(time.Date(1990, 5, 1, 0, 0, 0, 0, time.UTC), time.Now().UTC()),
+							Country: "RU",
+						}, []int(nil),
+					).Return(3, nil)
+					return s
+				}(),
+				cfg: &config{},
+			},
+			wantHeaders: map[string]string{
+				"ETag": `"3"`,
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "Age does not correspond to birth date (400)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					body := `{"surname": "Ivanov", "name": "Ivan", "birthDate": "1990-05-01", "age": 5}`
+					r := httptest.NewRequest("PUT", "/v1/people/{id}", strings.NewReader(body))
+					r.Header.Set("Content-Type", models.MimeTypeEditedPersonDataV2)
+					ctx := chi.NewRouteContext()
+					ctx.URLParams.Add("id", "101")
+					return r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, ctx))
+				}(),
+			},
+			testService: testService{
+				cfg: &config{},
+			},
+			wantStatus: http.StatusBadRequest,
+		},
 		{
 			name: "Person not found - bad id (404)",
 			args: args{
@@ -201,10 +483,15 @@ func TestService_editPersonData(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			s := &Service{
 				storage: tt.testService.storage,
+				cfg:     tt.testService.cfg,
 			}
 			s.editPersonData(tt.args.w, tt.args.r)
 
 			require.Equal(t, tt.wantStatus, tt.args.w.Code)
+
+			for k, v := range tt.wantHeaders {
+				require.Equal(t, v, tt.args.w.Result().Header.Get(k))
+			}
 		})
 	}
 }
@@ -217,3 +504,25 @@ func (e ErrPersonDataNotFoundTest) Error() string {
 
 func (e ErrPersonDataNotFoundTest) ImplementsPersonDataNotFoundError() {
 }
+
+type ErrPersonDataVersionMismatchTest struct{}
+
+func (e ErrPersonDataVersionMismatchTest) Error() string {
+	return "person data version mismatch (test)"
+}
+
+func (e ErrPersonDataVersionMismatchTest) ImplementsPersonDataVersionMismatchError() {
+}
+
+type ErrPersonDataConstraintViolationTest struct{}
+
+func (e ErrPersonDataConstraintViolationTest) Error() string {
+	return "person data constraint violation (test)"
+}
+
+func (e ErrPersonDataConstraintViolationTest) ImplementsPersonDataConstraintViolationError() {
+}
+
+func (e ErrPersonDataConstraintViolationTest) InvalidField() (name, reason string) {
+	return "age", "Person's age must be between 0 and 150."
+}
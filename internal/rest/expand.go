@@ -0,0 +1,23 @@
+package rest
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Optional expanded representation of country code (expand=country).
+func expandCountry(r *http.Request) (expand bool, err error) {
+	for _, value := range r.URL.Query()["expand"] {
+		for _, field := range strings.Split(value, ",") {
+			switch field = strings.TrimSpace(field); field {
+			case "country":
+				expand = true
+			default:
+				return false, fmt.Errorf("Parameter 'expand' has unsupported value '%s'.", field)
+			}
+		}
+	}
+
+	return expand, nil
+}
@@ -0,0 +1,94 @@
+package rest
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/barpav/demography/internal/rest/models"
+	"github.com/rs/zerolog/log"
+)
+
+// Full (unpaged) search result is streamed in requested format.
+func (s *Service) exportSearchResultV1(w http.ResponseWriter, r *http.Request, format string) {
+	filters := &models.SearchFilters{}
+	err := readPersonFilters(r, filters)
+
+	if err != nil {
+		respondWithInvalidRequest(w, err)
+		return
+	}
+
+	if !s.withinHistory(w, r, filters.AsOf) {
+		return
+	}
+
+	var header, flush func() error
+	var write func(*models.EnrichedPersonDataV1) error
+	var filename string
+
+	switch format {
+	case models.MimeTypeCSV:
+		records := csv.NewWriter(w)
+		header = func() error { return records.Write(models.EnrichedPersonDataV1CSVHeader) }
+		write = func(data *models.EnrichedPersonDataV1) error { return records.Write(data.CSVRecord()) }
+		flush = func() error { records.Flush(); return records.Error() }
+		filename = "people.csv"
+	default:
+		encoder := json.NewEncoder(w)
+		header = func() error { return nil }
+		write = func(data *models.EnrichedPersonDataV1) error { return encoder.Encode(data) }
+		flush = func() error { return nil }
+		filename = "people.ndjson"
+	}
+
+	// response status can be set only before streaming is started
+	started := false
+	start := func() error {
+		if started {
+			return nil
+		}
+
+		started = true
+		w.Header().Set("Content-Type", format)
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+
+		return header()
+	}
+
+	exported := 0
+	err = s.storage.ExportSearchResultV1(r.Context(), filters, func(data *models.EnrichedPersonDataV1) (err error) {
+		err = start()
+
+		if err == nil {
+			err = write(data)
+		}
+
+		if err == nil {
+			exported++
+		}
+
+		return err
+	})
+
+	if err == nil {
+		err = start()
+	}
+
+	if err == nil {
+		err = flush()
+	}
+
+	if err != nil {
+		log.Err(err).Msg(fmt.Sprintf("Failed to export search result (v1) as '%s' (%d exported).", format, exported))
+
+		if !started {
+			respondWithProblem(w, http.StatusInternalServerError)
+		}
+
+		return
+	}
+
+	log.Info().Msg(fmt.Sprintf("Search results exported as '%s': %d", format, exported))
+}
@@ -0,0 +1,69 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/barpav/demography/internal/rest/models"
+	"github.com/barpav/demography/internal/rest/negotiation"
+	"github.com/rs/zerolog/log"
+)
+
+// https://barpav.github.io/demography-api/#/demographics/get_demographics_age_histogram
+func (s *Service) getAgeHistogram(w http.ResponseWriter, r *http.Request) {
+	switch negotiation.Accept(r.Header.Get("Accept"), models.MimeTypeAgeHistogramV1, models.MimeTypeCSV) {
+	case models.MimeTypeAgeHistogramV1:
+		s.getAgeHistogramV1(w, r, false)
+	case models.MimeTypeCSV:
+		s.getAgeHistogramV1(w, r, true)
+	default:
+		respondWithProblem(w, http.StatusNotAcceptable)
+		return
+	}
+}
+
+func (s *Service) getAgeHistogramV1(w http.ResponseWriter, r *http.Request, csv bool) {
+	filters := &models.SearchFilters{}
+	err := readPersonFilters(r, filters)
+
+	const widthDefault, widthMin, widthMax = 5, 1, 50
+	width, widthErr := boundedIntegerQueryParameter(r, "width", widthDefault, widthMin, widthMax)
+	err = errors.Join(err, widthErr)
+
+	if err != nil {
+		respondWithInvalidRequest(w, err)
+		return
+	}
+
+	if !s.withinHistory(w, r, filters.AsOf) {
+		return
+	}
+
+	var result *models.AgeHistogramV1
+	result, err = s.storage.AgeHistogramV1(r.Context(), filters, int(width))
+
+	if err != nil {
+		log.Err(err).Msg("Failed to receive age histogram (v1).")
+		respondWithProblem(w, http.StatusInternalServerError)
+		return
+	}
+
+	if csv {
+		w.Header().Set("Content-Type", models.MimeTypeCSV)
+		w.Header().Set("Content-Disposition", `attachment; filename="age-histogram.csv"`)
+		err = result.SerializeCSV(w)
+	} else {
+		w.Header().Set("Content-Type", models.MimeTypeAgeHistogramV1)
+		err = json.NewEncoder(w).Encode(result)
+	}
+
+	if err != nil {
+		log.Err(err).Msg("Failed to serialize age histogram (v1).")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	log.Info().Msg(fmt.Sprintf("Age histogram returned: %d people in %d buckets.", result.Total, len(result.Buckets)))
+}
@@ -0,0 +1,181 @@
+package rest
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/barpav/demography/internal/rest/mocks"
+	"github.com/barpav/demography/internal/rest/models"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_getAgeHistogram(t *testing.T) {
+	histogram := func() *models.AgeHistogramV1 {
+		return &models.AgeHistogramV1{
+			BucketWidth: 10,
+			Total:       6,
+			Buckets: []*models.AgeBucketV1{
+				{From: 20, To: 29, Male: 1, Female: 2},
+				{From: 30, To: 39, Male: 2, Unknown: 1},
+			},
+		}
+	}
+
+	type testService struct {
+		storage Storage
+	}
+	type args struct {
+		w *httptest.ResponseRecorder
+		r *http.Request
+	}
+	tests := []struct {
+		name        string
+		testService testService
+		args        args
+		wantHeaders map[string]string
+		wantBody    *models.AgeHistogramV1
+		wantCSV     string
+		wantStatus  int
+	}{
+		{
+			name: "JSON histogram (200)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					r := httptest.NewRequest("GET", "/v1/demographics/age-histogram?country=RU&width=10", nil)
+					r.Header.Set("Accept", models.MimeTypeAgeHistogramV1)
+					return r
+				}(),
+			},
+			testService: testService{
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("AgeHistogramV1", mock.Anything, &models.SearchFilters{Country: "RU"}, 10).Return(histogram(), nil)
+					return s
+				}(),
+			},
+			wantHeaders: map[string]string{
+				"Content-Type": models.MimeTypeAgeHistogramV1,
+			},
+			wantBody:   histogram(),
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "CSV histogram (200)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					r := httptest.NewRequest("GET", "/v1/demographics/age-histogram?width=10", nil)
+					r.Header.Set("Accept", models.MimeTypeCSV)
+					return r
+				}(),
+			},
+			testService: testService{
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("AgeHistogramV1", mock.Anything, &models.SearchFilters{}, 10).Return(histogram(), nil)
+					return s
+				}(),
+			},
+			wantHeaders: map[string]string{
+				"Content-Type":        models.MimeTypeCSV,
+				"Content-Disposition": `attachment; filename="age-histogram.csv"`,
+			},
+			wantCSV:    "from,to,male,female,unknown\n20,29,1,2,0\n30,39,2,0,1\n",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "Default bucket width (200)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("GET", "/v1/demographics/age-histogram", nil),
+			},
+			testService: testService{
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("AgeHistogramV1", mock.Anything, &models.SearchFilters{}, 5).Return(&models.AgeHistogramV1{BucketWidth: 5}, nil)
+					return s
+				}(),
+			},
+			wantHeaders: map[string]string{
+				"Content-Type": models.MimeTypeAgeHistogramV1,
+			},
+			wantBody:   &models.AgeHistogramV1{BucketWidth: 5},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "Incorrect bucket width (400)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("GET", "/v1/demographics/age-histogram?width=0", nil),
+			},
+			wantHeaders: map[string]string{},
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			name: "Requested media type is not supported (406)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					r := httptest.NewRequest("GET", "/v1/demographics/age-histogram", nil)
+					r.Header.Set("Accept", "application/xml")
+					return r
+				}(),
+			},
+			wantHeaders: map[string]string{},
+			wantStatus:  http.StatusNotAcceptable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Service{
+				storage: tt.testService.storage,
+			}
+			s.getAgeHistogram(tt.args.w, tt.args.r)
+
+			for k, v := range tt.wantHeaders {
+				require.Equal(t, v, func() string {
+					h := tt.args.w.Result().Header
+					if h == nil {
+						return ""
+					}
+					v := h[k]
+					if len(v) == 0 {
+						return ""
+					}
+					return v[0]
+				}())
+			}
+
+			require.Equal(t, tt.wantStatus, tt.args.w.Code)
+
+			if tt.wantCSV != "" {
+				require.Equal(t, tt.wantCSV, tt.args.w.Body.String())
+				return
+			}
+
+			if tt.wantBody == nil {
+				return
+			}
+
+			var body *models.AgeHistogramV1
+			decoded := models.AgeHistogramV1{}
+			err := json.NewDecoder(tt.args.w.Body).Decode(&decoded)
+
+			if err != nil && err != io.EOF {
+				t.Fatal(err)
+			}
+
+			if err == nil {
+				body = &decoded
+			}
+
+			require.Equal(t, body, tt.wantBody)
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/barpav/demography/internal/rest/models"
+	"github.com/barpav/demography/internal/rest/negotiation"
+	"github.com/rs/zerolog/log"
+)
+
+// https://barpav.github.io/demography-api/#/people/get_people_deleted
+func (s *Service) getDeletedPeople(w http.ResponseWriter, r *http.Request) {
+	switch negotiation.Accept(r.Header.Get("Accept"), models.MimeTypeDeletedPeopleV1) {
+	case models.MimeTypeDeletedPeopleV1:
+		s.getDeletedPeopleV1(w, r)
+	default:
+		respondWithProblem(w, http.StatusNotAcceptable)
+		return
+	}
+}
+
+func (s *Service) getDeletedPeopleV1(w http.ResponseWriter, r *http.Request) {
+	after, err := integerQueryParameter(r, "after")
+
+	const limitDefault, limitMin, limitMax = 30, 1, 100
+	limit, limitErr := boundedIntegerQueryParameter(r, "limit", limitDefault, limitMin, limitMax)
+	err = errors.Join(err, limitErr)
+
+	if err != nil {
+		respondWithInvalidRequest(w, err)
+		return
+	}
+
+	var result *models.DeletedPeopleV1
+	result, err = s.storage.DeletedPeopleV1(r.Context(), after, int(limit))
+
+	if err != nil {
+		log.Err(err).Msg("Failed to receive deleted people (v1).")
+		respondWithProblem(w, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", models.MimeTypeDeletedPeopleV1)
+	err = json.NewEncoder(w).Encode(result)
+
+	if err != nil {
+		log.Err(err).Msg("Failed to serialize deleted people (v1).")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	log.Info().Msg(fmt.Sprintf("Deleted people: %d", result.Total))
+}
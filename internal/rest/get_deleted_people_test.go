@@ -0,0 +1,129 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/barpav/demography/internal/rest/mocks"
+	"github.com/barpav/demography/internal/rest/models"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_getDeletedPeople(t *testing.T) {
+	deleted := func() *models.DeletedPeopleV1 {
+		deletedAt := time.Date(2023, 9, 1, 12, 0, 0, 0, time.UTC)
+		return &models.DeletedPeopleV1{
+			Total: 1,
+			Data: []*models.DeletedPersonDataV1{
+				{
+					EnrichedPersonDataV1: models.EnrichedPersonDataV1{
+						Id:      101,
+						Surname: "Ivanov",
+						Name:    "Ivan",
+						Age:     42,
+					},
+					DeletedAt: deletedAt,
+					PurgeAt:   deletedAt.Add(30 * 24 * time.Hour),
+				},
+			},
+		}
+	}
+
+	type testService struct {
+		storage Storage
+	}
+	type args struct {
+		w *httptest.ResponseRecorder
+		r *http.Request
+	}
+	tests := []struct {
+		name        string
+		testService testService
+		args        args
+		wantBody    *models.DeletedPeopleV1
+		wantStatus  int
+	}{
+		{
+			name: "Deleted people (200)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("GET", "/v1/people:deleted?after=100&limit=10", nil),
+			},
+			testService: testService{
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("DeletedPeopleV1", mock.Anything, int64(100), 10).Return(deleted(), nil)
+					return s
+				}(),
+			},
+			wantBody:   deleted(),
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "Default paging (200)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("GET", "/v1/people:deleted", nil),
+			},
+			testService: testService{
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("DeletedPeopleV1", mock.Anything, int64(0), 30).Return(&models.DeletedPeopleV1{}, nil)
+					return s
+				}(),
+			},
+			wantBody:   &models.DeletedPeopleV1{},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "Incorrect limit (400)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest("GET", "/v1/people:deleted?limit=1000", nil),
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Requested media type is not supported (406)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					r := httptest.NewRequest("GET", "/v1/people:deleted", nil)
+					r.Header.Set("Accept", models.MimeTypeSearchResultV1)
+					return r
+				}(),
+			},
+			wantStatus: http.StatusNotAcceptable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Service{
+				storage: tt.testService.storage,
+			}
+			s.getDeletedPeople(tt.args.w, tt.args.r)
+
+			require.Equal(t, tt.wantStatus, tt.args.w.Code)
+
+			if tt.wantBody == nil {
+				return
+			}
+
+			require.Equal(t, models.MimeTypeDeletedPeopleV1, tt.args.w.Result().Header.Get("Content-Type"))
+
+			body := &models.DeletedPeopleV1{}
+			err := json.NewDecoder(tt.args.w.Body).Decode(body)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			require.Equal(t, tt.wantBody, body)
+		})
+	}
+}
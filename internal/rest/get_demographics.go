@@ -0,0 +1,86 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/barpav/demography/internal/rest/models"
+	"github.com/barpav/demography/internal/rest/negotiation"
+	"github.com/rs/zerolog/log"
+)
+
+// https://barpav.github.io/demography-api/#/demographics/get_demographics
+func (s *Service) getDemographics(w http.ResponseWriter, r *http.Request) {
+	switch negotiation.Accept(r.Header.Get("Accept"), models.MimeTypeDemographicsV1) {
+	case models.MimeTypeDemographicsV1:
+		s.getDemographicsV1(w, r)
+	default:
+		respondWithProblem(w, http.StatusNotAcceptable)
+		return
+	}
+}
+
+func (s *Service) getDemographicsV1(w http.ResponseWriter, r *http.Request) {
+	filters := &models.SearchFilters{}
+	err := readPersonFilters(r, filters)
+
+	var grouping *models.DemographicsGrouping
+	var groupingErr error
+	grouping, groupingErr = demographicsGrouping(r)
+	err = errors.Join(err, groupingErr)
+
+	if err != nil {
+		respondWithInvalidRequest(w, err)
+		return
+	}
+
+	if !s.withinHistory(w, r, filters.AsOf) {
+		return
+	}
+
+	var result *models.DemographicsV1
+	result, err = s.storage.DemographicsV1(r.Context(), filters, grouping)
+
+	if err != nil {
+		log.Err(err).Msg("Failed to receive demographics (v1).")
+		respondWithProblem(w, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", models.MimeTypeDemographicsV1)
+	err = json.NewEncoder(w).Encode(result)
+
+	if err != nil {
+		log.Err(err).Msg("Failed to serialize demographics (v1).")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	log.Info().Msg(fmt.Sprintf("Demographics returned: %d people in %d groups.", result.Total, len(result.Groups)))
+}
+
+// Parameter 'groupBy' is a comma-separated list: gender, country.
+func demographicsGrouping(r *http.Request) (grouping *models.DemographicsGrouping, err error) {
+	grouping = &models.DemographicsGrouping{}
+	param := r.URL.Query().Get("groupBy")
+
+	if param == "" {
+		return grouping, nil
+	}
+
+	for _, field := range strings.Split(param, ",") {
+		switch strings.TrimSpace(field) {
+		case "gender":
+			grouping.Gender = true
+		case "country":
+			grouping.Country = true
+		default:
+			return nil, fmt.Errorf("Invalid parameter 'groupBy': unknown field '%s' (gender, country expected).", field)
+		}
+	}
+
+	return grouping, nil
+}
@@ -0,0 +1,179 @@
+package rest
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/barpav/demography/internal/rest/mocks"
+	"github.com/barpav/demography/internal/rest/models"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_getDemographics(t *testing.T) {
+	type testService struct {
+		storage Storage
+	}
+	type args struct {
+		w *httptest.ResponseRecorder
+		r *http.Request
+	}
+	tests := []struct {
+		name        string
+		testService testService
+		args        args
+		wantHeaders map[string]string
+		wantBody    *models.DemographicsV1
+		wantStatus  int
+	}{
+		{
+			name: "Grouped by gender with filters (200)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					r := httptest.NewRequest("GET", "/v1/demographics?country=RU&groupBy=gender", nil)
+					r.Header.Set("Accept", models.MimeTypeDemographicsV1)
+					return r
+				}(),
+			},
+			testService: testService{
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("DemographicsV1", mock.Anything,
+						&models.SearchFilters{Country: "RU"},
+						&models.DemographicsGrouping{Gender: true},
+					).Return(&models.DemographicsV1{
+						Total: 3,
+						Groups: []*models.DemographicsGroupV1{
+							{
+								Gender: "male",
+								Count:  2,
+								Age:    &models.AgeStatisticsV1{Count: 2, Min: 30, Max: 50, Mean: 40, Median: 40},
+							},
+							{
+								Gender: "female",
+								Count:  1,
+							},
+						},
+					}, nil)
+					return s
+				}(),
+			},
+			wantHeaders: map[string]string{
+				"Content-Type": models.MimeTypeDemographicsV1,
+			},
+			wantBody: &models.DemographicsV1{
+				Total: 3,
+				Groups: []*models.DemographicsGroupV1{
+					{
+						Gender: "male",
+						Count:  2,
+						Age:    &models.AgeStatisticsV1{Count: 2, Min: 30, Max: 50, Mean: 40, Median: 40},
+					},
+					{
+						Gender: "female",
+						Count:  1,
+					},
+				},
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "Grouped by gender and country (200)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					r := httptest.NewRequest("GET", "/v1/demographics?groupBy=gender,country", nil)
+					return r
+				}(),
+			},
+			testService: testService{
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("DemographicsV1", mock.Anything,
+						&models.SearchFilters{},
+						&models.DemographicsGrouping{Gender: true, Country: true},
+					).Return(&models.DemographicsV1{}, nil)
+					return s
+				}(),
+			},
+			wantHeaders: map[string]string{
+				"Content-Type": models.MimeTypeDemographicsV1,
+			},
+			wantBody:   &models.DemographicsV1{},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "Incorrect parameters (400)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					r := httptest.NewRequest("GET", "/v1/demographics?age=old&groupBy=name", nil)
+					r.Header.Set("Accept", models.MimeTypeDemographicsV1)
+					return r
+				}(),
+			},
+			wantHeaders: map[string]string{},
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			name: "Requested media type is not supported (406)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					r := httptest.NewRequest("GET", "/v1/demographics", nil)
+					r.Header.Set("Accept", "application/xml")
+					return r
+				}(),
+			},
+			wantHeaders: map[string]string{},
+			wantStatus:  http.StatusNotAcceptable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Service{
+				storage: tt.testService.storage,
+			}
+			s.getDemographics(tt.args.w, tt.args.r)
+
+			for k, v := range tt.wantHeaders {
+				require.Equal(t, v, func() string {
+					h := tt.args.w.Result().Header
+					if h == nil {
+						return ""
+					}
+					v := h[k]
+					if len(v) == 0 {
+						return ""
+					}
+					return v[0]
+				}())
+			}
+
+			require.Equal(t, tt.wantStatus, tt.args.w.Code)
+
+			if tt.wantBody == nil {
+				return
+			}
+
+			var body *models.DemographicsV1
+			decoded := models.DemographicsV1{}
+			err := json.NewDecoder(tt.args.w.Body).Decode(&decoded)
+
+			if err != nil && err != io.EOF {
+				t.Fatal(err)
+			}
+
+			if err == nil {
+				body = &decoded
+			}
+
+			require.Equal(t, body, tt.wantBody)
+		})
+	}
+}
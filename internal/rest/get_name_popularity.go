@@ -0,0 +1,65 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/barpav/demography/internal/rest/models"
+	"github.com/barpav/demography/internal/rest/negotiation"
+	"github.com/rs/zerolog/log"
+)
+
+// https://barpav.github.io/demography-api/#/names/get_names
+// https://barpav.github.io/demography-api/#/names/get_surnames
+// https://barpav.github.io/demography-api/#/names/get_patronymics
+func (s *Service) getNamePopularity(field models.NameField) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch negotiation.Accept(r.Header.Get("Accept"), models.MimeTypeNamePopularityV1) {
+		case models.MimeTypeNamePopularityV1:
+			s.getNamePopularityV1(w, r, field)
+		default:
+			respondWithProblem(w, http.StatusNotAcceptable)
+			return
+		}
+	}
+}
+
+func (s *Service) getNamePopularityV1(w http.ResponseWriter, r *http.Request, field models.NameField) {
+	filters := &models.SearchFilters{}
+	err := readPersonFilters(r, filters)
+
+	const limitDefault, limitMin, limitMax = 10, 1, 100
+	limit, limitErr := boundedIntegerQueryParameter(r, "limit", limitDefault, limitMin, limitMax)
+	err = errors.Join(err, limitErr)
+
+	if err != nil {
+		respondWithInvalidRequest(w, err)
+		return
+	}
+
+	if !s.withinHistory(w, r, filters.AsOf) {
+		return
+	}
+
+	var result *models.NamePopularityV1
+	result, err = s.storage.NamePopularityV1(r.Context(), field, filters, int(limit))
+
+	if err != nil {
+		log.Err(err).Msg("Failed to receive name popularity (v1).")
+		respondWithProblem(w, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", models.MimeTypeNamePopularityV1)
+	err = json.NewEncoder(w).Encode(result)
+
+	if err != nil {
+		log.Err(err).Msg("Failed to serialize name popularity (v1).")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	log.Info().Msg(fmt.Sprintf("Popularity of %d values of '%s' returned.", len(result.Data), field))
+}
@@ -0,0 +1,160 @@
+package rest
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/barpav/demography/internal/rest/mocks"
+	"github.com/barpav/demography/internal/rest/models"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_getNamePopularity(t *testing.T) {
+	type testService struct {
+		storage Storage
+	}
+	type args struct {
+		field models.NameField
+		w     *httptest.ResponseRecorder
+		r     *http.Request
+	}
+	tests := []struct {
+		name        string
+		testService testService
+		args        args
+		wantHeaders map[string]string
+		wantBody    *models.NamePopularityV1
+		wantStatus  int
+	}{
+		{
+			name: "Top names with filters (200)",
+			args: args{
+				field: models.NameFieldName,
+				w:     httptest.NewRecorder(),
+				r: func() *http.Request {
+					r := httptest.NewRequest("GET", "/v1/names?country=RU&gender=male&limit=2", nil)
+					r.Header.Set("Accept", models.MimeTypeNamePopularityV1)
+					return r
+				}(),
+			},
+			testService: testService{
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("NamePopularityV1", mock.Anything, models.NameFieldName,
+						&models.SearchFilters{Country: "RU", Gender: "male"}, 2,
+					).Return(&models.NamePopularityV1{
+						Total: 4,
+						Data: []*models.NameRankV1{
+							{Value: "Ivan", Count: 3, Share: 75},
+							{Value: "Petr", Count: 1, Share: 25},
+						},
+					}, nil)
+					return s
+				}(),
+			},
+			wantHeaders: map[string]string{
+				"Content-Type": models.MimeTypeNamePopularityV1,
+			},
+			wantBody: &models.NamePopularityV1{
+				Total: 4,
+				Data: []*models.NameRankV1{
+					{Value: "Ivan", Count: 3, Share: 75},
+					{Value: "Petr", Count: 1, Share: 25},
+				},
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "Top surnames with default limit (200)",
+			args: args{
+				field: models.NameFieldSurname,
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("GET", "/v1/surnames", nil),
+			},
+			testService: testService{
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("NamePopularityV1", mock.Anything, models.NameFieldSurname, &models.SearchFilters{}, 10).
+						Return(&models.NamePopularityV1{}, nil)
+					return s
+				}(),
+			},
+			wantHeaders: map[string]string{
+				"Content-Type": models.MimeTypeNamePopularityV1,
+			},
+			wantBody:   &models.NamePopularityV1{},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "Incorrect parameters (400)",
+			args: args{
+				field: models.NameFieldPatronymic,
+				w:     httptest.NewRecorder(),
+				r:     httptest.NewRequest("GET", "/v1/patronymics?limit=1000", nil),
+			},
+			wantHeaders: map[string]string{},
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			name: "Requested media type is not supported (406)",
+			args: args{
+				field: models.NameFieldName,
+				w:     httptest.NewRecorder(),
+				r: func() *http.Request {
+					r := httptest.NewRequest("GET", "/v1/names", nil)
+					r.Header.Set("Accept", "application/xml")
+					return r
+				}(),
+			},
+			wantHeaders: map[string]string{},
+			wantStatus:  http.StatusNotAcceptable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Service{
+				storage: tt.testService.storage,
+			}
+			s.getNamePopularity(tt.args.field)(tt.args.w, tt.args.r)
+
+			for k, v := range tt.wantHeaders {
+				require.Equal(t, v, func() string {
+					h := tt.args.w.Result().Header
+					if h == nil {
+						return ""
+					}
+					v := h[k]
+					if len(v) == 0 {
+						return ""
+					}
+					return v[0]
+				}())
+			}
+
+			require.Equal(t, tt.wantStatus, tt.args.w.Code)
+
+			if tt.wantBody == nil {
+				return
+			}
+
+			var body *models.NamePopularityV1
+			decoded := models.NamePopularityV1{}
+			err := json.NewDecoder(tt.args.w.Body).Decode(&decoded)
+
+			if err != nil && err != io.EOF {
+				t.Fatal(err)
+			}
+
+			if err == nil {
+				body = &decoded
+			}
+
+			require.Equal(t, body, tt.wantBody)
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/barpav/demography/internal/rest/models"
+	"github.com/barpav/demography/internal/rest/negotiation"
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+)
+
+// https://barpav.github.io/demography-api/#/names/get_names__name_
+func (s *Service) getNameSummary(w http.ResponseWriter, r *http.Request) {
+	switch negotiation.Accept(r.Header.Get("Accept"), models.MimeTypeNameSummaryV1) {
+	case models.MimeTypeNameSummaryV1:
+		s.getNameSummaryV1(w, r)
+	default:
+		respondWithProblem(w, http.StatusNotAcceptable)
+		return
+	}
+}
+
+func (s *Service) getNameSummaryV1(w http.ResponseWriter, r *http.Request) {
+	filters := &models.SearchFilters{}
+	err := readPersonFilters(r, filters)
+
+	if err != nil {
+		respondWithInvalidRequest(w, err)
+		return
+	}
+
+	if !s.withinHistory(w, r, filters.AsOf) {
+		return
+	}
+
+	filters.Name = chi.URLParam(r, "name")
+
+	var result *models.NameSummaryV1
+	result, err = s.storage.NameSummaryV1(r.Context(), filters)
+
+	if err != nil {
+		log.Err(err).Msg("Failed to receive name summary (v1).")
+		respondWithProblem(w, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", models.MimeTypeNameSummaryV1)
+	err = json.NewEncoder(w).Encode(result)
+
+	if err != nil {
+		log.Err(err).Msg("Failed to serialize name summary (v1).")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	log.Info().Msg(fmt.Sprintf("Summary of name '%s' returned.", result.Name))
+}
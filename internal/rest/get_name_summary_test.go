@@ -0,0 +1,133 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/barpav/demography/internal/rest/mocks"
+	"github.com/barpav/demography/internal/rest/models"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_getNameSummary(t *testing.T) {
+	type testService struct {
+		storage Storage
+	}
+	type args struct {
+		w *httptest.ResponseRecorder
+		r *http.Request
+	}
+	tests := []struct {
+		name        string
+		testService testService
+		args        args
+		wantHeaders map[string]string
+		wantBody    *models.NameSummaryV1
+		wantStatus  int
+	}{
+		{
+			name: "OK (200)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					r := httptest.NewRequest("GET", "/v1/names/{name}?country=RU", nil)
+					r.Header.Set("Accept", models.MimeTypeNameSummaryV1)
+					ctx := chi.NewRouteContext()
+					ctx.URLParams.Add("name", "Ivan")
+					return r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, ctx))
+				}(),
+			},
+			testService: testService{
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("NameSummaryV1", mock.Anything, &models.SearchFilters{Name: "Ivan", Country: "RU"}).
+						Return(&models.NameSummaryV1{Name: "Ivan", Count: 12, AverageAge: 47.5}, nil)
+					return s
+				}(),
+			},
+			wantHeaders: map[string]string{
+				"Content-Type": models.MimeTypeNameSummaryV1,
+			},
+			wantBody:   &models.NameSummaryV1{Name: "Ivan", Count: 12, AverageAge: 47.5},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "Incorrect parameters (400)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					r := httptest.NewRequest("GET", "/v1/names/{name}?age=old", nil)
+					ctx := chi.NewRouteContext()
+					ctx.URLParams.Add("name", "Ivan")
+					return r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, ctx))
+				}(),
+			},
+			wantHeaders: map[string]string{},
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			name: "Requested media type is not supported (406)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					r := httptest.NewRequest("GET", "/v1/names/{name}", nil)
+					r.Header.Set("Accept", "application/xml")
+					ctx := chi.NewRouteContext()
+					ctx.URLParams.Add("name", "Ivan")
+					return r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, ctx))
+				}(),
+			},
+			wantHeaders: map[string]string{},
+			wantStatus:  http.StatusNotAcceptable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Service{
+				storage: tt.testService.storage,
+			}
+			s.getNameSummary(tt.args.w, tt.args.r)
+
+			for k, v := range tt.wantHeaders {
+				require.Equal(t, v, func() string {
+					h := tt.args.w.Result().Header
+					if h == nil {
+						return ""
+					}
+					v := h[k]
+					if len(v) == 0 {
+						return ""
+					}
+					return v[0]
+				}())
+			}
+
+			require.Equal(t, tt.wantStatus, tt.args.w.Code)
+
+			if tt.wantBody == nil {
+				return
+			}
+
+			var body *models.NameSummaryV1
+			decoded := models.NameSummaryV1{}
+			err := json.NewDecoder(tt.args.w.Body).Decode(&decoded)
+
+			if err != nil && err != io.EOF {
+				t.Fatal(err)
+			}
+
+			if err == nil {
+				body = &decoded
+			}
+
+			require.Equal(t, body, tt.wantBody)
+		})
+	}
+}
@@ -5,19 +5,23 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/barpav/demography/internal/rest/models"
+	"github.com/barpav/demography/internal/rest/negotiation"
 	"github.com/go-chi/chi/v5"
 	"github.com/rs/zerolog/log"
 )
 
 // https://barpav.github.io/demography-api/#/people/get_people__id_
 func (s *Service) getPersonData(w http.ResponseWriter, r *http.Request) {
-	switch r.Header.Get("Accept") {
-	case "", models.MimeTypeEnrichedPersonDataV1:
+	switch negotiation.Accept(r.Header.Get("Accept"), models.MimeTypeEnrichedPersonDataV1, models.MimeTypeEnrichedPersonDataV2) {
+	case models.MimeTypeEnrichedPersonDataV1:
 		s.getPersonDataV1(w, r)
+	case models.MimeTypeEnrichedPersonDataV2:
+		s.getPersonDataV2(w, r)
 	default:
-		w.WriteHeader(http.StatusNotAcceptable)
+		respondWithProblem(w, http.StatusNotAcceptable)
 		return
 	}
 }
@@ -26,24 +30,61 @@ func (s *Service) getPersonDataV1(w http.ResponseWriter, r *http.Request) {
 	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 0)
 
 	if err != nil {
-		w.WriteHeader(http.StatusNotFound)
+		respondWithProblem(w, http.StatusNotFound)
+		return
+	}
+
+	var expand bool
+	expand, err = expandCountry(r)
+
+	var asOf time.Time
+	if err == nil {
+		asOf, err = timeQueryParameter(r, "as_of")
+	}
+
+	if err != nil {
+		respondWithInvalidRequest(w, err)
+		return
+	}
+
+	if !s.withinHistory(w, r, asOf) {
 		return
 	}
 
 	var data *models.EnrichedPersonDataV1
-	data, err = s.storage.EnrichedPersonDataV1(r.Context(), id)
+
+	if asOf.IsZero() {
+		data, err = s.storage.EnrichedPersonDataV1(r.Context(), id)
+	} else {
+		data, err = s.storage.EnrichedPersonDataV1AsOf(r.Context(), id, asOf)
+	}
 
 	if err != nil {
 		log.Err(err).Msg("Failed to receive enriched person data (v1) by id.")
-		w.WriteHeader(http.StatusInternalServerError)
+		respondWithProblem(w, http.StatusInternalServerError)
 		return
 	}
 
 	if data == nil {
-		w.WriteHeader(http.StatusNotFound)
+		respondWithProblem(w, http.StatusNotFound)
+		return
+	}
+
+	if !asOf.IsZero() && !s.pastPersonDataAllowed(w, r, id) {
+		return
+	}
+
+	w.Header().Set("ETag", entityTag(data.Version))
+
+	if notModified(r, data.Version) {
+		w.WriteHeader(http.StatusNotModified)
 		return
 	}
 
+	if expand {
+		data.CountryDetails = models.NewCountryV1(data.Country)
+	}
+
 	w.Header().Set("Content-Type", models.MimeTypeEnrichedPersonDataV1)
 	err = json.NewEncoder(w).Encode(data)
 
@@ -55,3 +96,74 @@ func (s *Service) getPersonDataV1(w http.ResponseWriter, r *http.Request) {
 
 	log.Info().Msg(fmt.Sprintf("Person data with id '%d' returned.", id))
 }
+
+func (s *Service) getPersonDataV2(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 0)
+
+	if err != nil {
+		respondWithProblem(w, http.StatusNotFound)
+		return
+	}
+
+	var expand bool
+	expand, err = expandCountry(r)
+
+	var asOf time.Time
+	if err == nil {
+		asOf, err = timeQueryParameter(r, "as_of")
+	}
+
+	if err != nil {
+		respondWithInvalidRequest(w, err)
+		return
+	}
+
+	if !s.withinHistory(w, r, asOf) {
+		return
+	}
+
+	var data *models.EnrichedPersonDataV2
+
+	if asOf.IsZero() {
+		data, err = s.storage.EnrichedPersonDataV2(r.Context(), id)
+	} else {
+		data, err = s.storage.EnrichedPersonDataV2AsOf(r.Context(), id, asOf)
+	}
+
+	if err != nil {
+		log.Err(err).Msg("Failed to receive enriched person data (v2) by id.")
+		respondWithProblem(w, http.StatusInternalServerError)
+		return
+	}
+
+	if data == nil {
+		respondWithProblem(w, http.StatusNotFound)
+		return
+	}
+
+	if !asOf.IsZero() && !s.pastPersonDataAllowed(w, r, id) {
+		return
+	}
+
+	w.Header().Set("ETag", entityTag(data.Version))
+
+	if notModified(r, data.Version) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	if expand {
+		data.CountryDetails = models.NewCountryV1(data.Country)
+	}
+
+	w.Header().Set("Content-Type", models.MimeTypeEnrichedPersonDataV2)
+	err = json.NewEncoder(w).Encode(data)
+
+	if err != nil {
+		log.Err(err).Msg("Failed to serialize enriched person data (v2).")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	log.Info().Msg(fmt.Sprintf("Person data with id '%d' returned (v2).", id))
+}
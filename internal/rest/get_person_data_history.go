@@ -0,0 +1,124 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/barpav/demography/internal/rest/models"
+	"github.com/barpav/demography/internal/rest/negotiation"
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+)
+
+// https://barpav.github.io/demography-api/#/people/get_people__id__history
+func (s *Service) getPersonDataHistory(w http.ResponseWriter, r *http.Request) {
+	switch negotiation.Accept(r.Header.Get("Accept"), models.MimeTypePersonDataHistoryV1) {
+	case models.MimeTypePersonDataHistoryV1:
+		s.getPersonDataHistoryV1(w, r)
+	default:
+		respondWithProblem(w, http.StatusNotAcceptable)
+		return
+	}
+}
+
+func (s *Service) getPersonDataHistoryV1(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 0)
+
+	if err != nil {
+		respondWithProblem(w, http.StatusNotFound)
+		return
+	}
+
+	var after int64
+	after, err = integerQueryParameter(r, "after")
+
+	const limitDefault, limitMin, limitMax = 30, 1, 100
+	limit, limitErr := boundedIntegerQueryParameter(r, "limit", limitDefault, limitMin, limitMax)
+	err = errors.Join(err, limitErr)
+
+	if err != nil {
+		respondWithInvalidRequest(w, err)
+		return
+	}
+
+	var history *models.PersonDataHistoryV1
+	history, err = s.storage.PersonDataHistoryV1(r.Context(), id, after, int(limit))
+
+	if err != nil {
+		log.Err(err).Msg("Failed to receive person data history (v1).")
+		respondWithProblem(w, http.StatusInternalServerError)
+		return
+	}
+
+	// history is kept even after person data is purged (without the data itself)
+	if history.Total == 0 && after == 0 {
+		respondWithProblem(w, http.StatusNotFound)
+		return
+	}
+
+	if !s.pastPersonDataAllowed(w, r, id) {
+		return
+	}
+
+	w.Header().Set("Content-Type", models.MimeTypePersonDataHistoryV1)
+	err = json.NewEncoder(w).Encode(history)
+
+	if err != nil {
+		log.Err(err).Msg("Failed to serialize person data history (v1).")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	log.Info().Msg(fmt.Sprintf("Person data history with id '%d' received: %d changes.", id, history.Total))
+}
+
+// Like deleted people list, past data of people that don't exist anymore (deleted or purged)
+// requires deletion scope. Responds with a problem if it's not allowed.
+func (s *Service) pastPersonDataAllowed(w http.ResponseWriter, r *http.Request, id int64) bool {
+	if allowed(r, ScopePeopleDelete) {
+		return true
+	}
+
+	exists, err := s.storage.PersonDataExists(r.Context(), id)
+
+	if err != nil {
+		log.Err(err).Msg("Failed to check person data existence.")
+		respondWithProblem(w, http.StatusInternalServerError)
+		return false
+	}
+
+	if !exists {
+		respondWithProblemDetail(w, http.StatusForbidden, fmt.Sprintf("Scope '%s' is required.", ScopePeopleDelete))
+		return false
+	}
+
+	return true
+}
+
+// Data can't be restored at points in time (as_of) earlier than the start of history.
+// Checked by operations after authorization, since it takes a database query. Responds with a problem if it's not.
+func (s *Service) withinHistory(w http.ResponseWriter, r *http.Request, asOf time.Time) bool {
+	if asOf.IsZero() {
+		return true
+	}
+
+	start, err := s.storage.HistoryStart(r.Context())
+
+	if err != nil {
+		log.Err(err).Msg("Failed to receive start of history.")
+		respondWithProblem(w, http.StatusInternalServerError)
+		return false
+	}
+
+	if asOf.Before(start) {
+		respondWithInvalidRequest(w, fmt.Errorf("Parameter 'as_of' cannot be earlier than the start of history (%s).",
+			start.UTC().Format(time.RFC3339)))
+		return false
+	}
+
+	return true
+}
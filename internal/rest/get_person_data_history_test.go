@@ -0,0 +1,252 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/barpav/demography/internal/rest/mocks"
+	"github.com/barpav/demography/internal/rest/models"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_getPersonDataHistory(t *testing.T) {
+	history := func() *models.PersonDataHistoryV1 {
+		return &models.PersonDataHistoryV1{
+			Total: 2,
+			Data: []*models.PersonDataChangeV1{
+				{
+					Id:        1,
+					Change:    models.PersonDataChangeCreate,
+					Version:   1,
+					Actor:     "address:192.0.2.1",
+					ChangedAt: time.Date(2023, 9, 1, 12, 0, 0, 0, time.UTC),
+					After:     map[string]any{"surname": "Ivanov", "name": "Ivan", "country": "RU"},
+				},
+				{
+					Id:        7,
+					Change:    models.PersonDataChangeUpdate,
+					Version:   2,
+					Actor:     "address:192.0.2.2",
+					ChangedAt: time.Date(2023, 9, 2, 12, 0, 0, 0, time.UTC),
+					Before:    map[string]any{"country": "RU"},
+					After:     map[string]any{"country": "KZ"},
+				},
+			},
+		}
+	}
+	request := func(id, query string) *http.Request {
+		r := httptest.NewRequest("GET", "/v1/people/{id}/history"+query, nil)
+		ctx := chi.NewRouteContext()
+		ctx.URLParams.Add("id", id)
+		return r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, ctx))
+	}
+
+	type testService struct {
+		storage Storage
+	}
+	type args struct {
+		w *httptest.ResponseRecorder
+		r *http.Request
+	}
+	tests := []struct {
+		name        string
+		testService testService
+		args        args
+		wantBody    *models.PersonDataHistoryV1
+		wantStatus  int
+	}{
+		{
+			name: "History received (200)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: request("101", ""),
+			},
+			testService: testService{
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("PersonDataHistoryV1", mock.Anything, int64(101), int64(0), 30).Return(history(), nil)
+					s.On("PersonDataExists", mock.Anything, int64(101)).Return(true, nil)
+					return s
+				}(),
+			},
+			wantBody:   history(),
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "Next page is empty (200)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: request("101", "?after=7&limit=2"),
+			},
+			testService: testService{
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("PersonDataHistoryV1", mock.Anything, int64(101), int64(7), 2).Return(&models.PersonDataHistoryV1{}, nil)
+					s.On("PersonDataExists", mock.Anything, int64(101)).Return(true, nil)
+					return s
+				}(),
+			},
+			wantBody:   &models.PersonDataHistoryV1{},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "History of purged person received with deletion scope (200)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					r := request("101", "")
+					return r.WithContext(context.WithValue(r.Context(), principalKey{}, &principal{scopes: []string{ScopePeopleDelete}}))
+				}(),
+			},
+			testService: testService{
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("PersonDataHistoryV1", mock.Anything, int64(101), int64(0), 30).Return(history(), nil)
+					return s
+				}(),
+			},
+			wantBody:   history(),
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "History of purged person requires deletion scope (403)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					r := request("101", "")
+					return r.WithContext(context.WithValue(r.Context(), principalKey{}, &principal{scopes: []string{ScopePeopleRead}}))
+				}(),
+			},
+			testService: testService{
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("PersonDataHistoryV1", mock.Anything, int64(101), int64(0), 30).Return(history(), nil)
+					s.On("PersonDataExists", mock.Anything, int64(101)).Return(false, nil)
+					return s
+				}(),
+			},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name: "Incorrect limit (400)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: request("101", "?limit=0"),
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Person not found in DB (404)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: request("101", ""),
+			},
+			testService: testService{
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("PersonDataHistoryV1", mock.Anything, int64(101), int64(0), 30).Return(&models.PersonDataHistoryV1{}, nil)
+					return s
+				}(),
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name: "Person not found - bad id (404)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: request("some-text", ""),
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name: "Requested media type is not supported (406)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					r := request("101", "")
+					r.Header.Set("Accept", models.MimeTypeEnrichedPersonDataV1)
+					return r
+				}(),
+			},
+			wantStatus: http.StatusNotAcceptable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Service{
+				storage: tt.testService.storage,
+			}
+			s.getPersonDataHistory(tt.args.w, tt.args.r)
+
+			require.Equal(t, tt.wantStatus, tt.args.w.Code)
+
+			if tt.wantBody == nil {
+				return
+			}
+
+			require.Equal(t, models.MimeTypePersonDataHistoryV1, tt.args.w.Result().Header.Get("Content-Type"))
+
+			body := &models.PersonDataHistoryV1{}
+			err := json.NewDecoder(tt.args.w.Body).Decode(body)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			require.Equal(t, tt.wantBody, body)
+		})
+	}
+}
+
+func TestService_withinHistory(t *testing.T) {
+	start := time.Date(2023, 9, 1, 12, 0, 0, 0, time.UTC)
+	historyStarted := func() *mocks.Storage {
+		s := mocks.NewStorage(t)
+		s.On("HistoryStart", mock.Anything).Return(start, nil)
+		return s
+	}
+
+	tests := []struct {
+		name       string
+		storage    Storage
+		asOf       time.Time
+		want       bool
+		wantStatus int
+	}{
+		{
+			name:       "Current data",
+			want:       true,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "Point in time after start of history",
+			storage:    historyStarted(),
+			asOf:       start,
+			want:       true,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "Point in time before start of history (400)",
+			storage:    historyStarted(),
+			asOf:       start.Add(-time.Second),
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Service{storage: tt.storage}
+			w := httptest.NewRecorder()
+
+			require.Equal(t, tt.want, s.withinHistory(w, httptest.NewRequest("GET", "/v1/people", nil), tt.asOf))
+			require.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
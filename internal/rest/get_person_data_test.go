@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/barpav/demography/internal/rest/mocks"
 	"github.com/barpav/demography/internal/rest/models"
@@ -54,6 +55,7 @@ func TestService_getPersonData(t *testing.T) {
 							Age:        50,
 							Gender:     "male",
 							Country:    "RU",
+							Version:    4,
 						},
 						nil)
 					return s
@@ -61,6 +63,7 @@ func TestService_getPersonData(t *testing.T) {
 			},
 			wantHeaders: map[string]string{
 				"Content-Type": models.MimeTypeEnrichedPersonDataV1,
+				"ETag":         `"4"`,
 			},
 			wantBody: &models.EnrichedPersonDataV1{
 				Id:         101,
@@ -73,6 +76,173 @@ func TestService_getPersonData(t *testing.T) {
 			},
 			wantStatus: http.StatusOK,
 		},
+		{
+			name: "Point-in-time data (200)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					r := httptest.NewRequest("GET", "/v1/people/{id}?as_of=2023-09-01T12:00:00Z", nil)
+					ctx := chi.NewRouteContext()
+					ctx.URLParams.Add("id", "101")
+					return r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, ctx))
+				}(),
+			},
+			testService: testService{
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("HistoryStart", mock.Anything).Return(time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC), nil)
+					s.On("EnrichedPersonDataV1AsOf", mock.Anything, int64(101), time.Date(2023, 9, 1, 12, 0, 0, 0, time.UTC)).Return(
+						&models.EnrichedPersonDataV1{Id: 101, Surname: "Ivanov", Name: "Ivan", Country: "RU", Version: 2}, nil)
+					s.On("PersonDataExists", mock.Anything, int64(101)).Return(true, nil)
+					return s
+				}(),
+			},
+			wantHeaders: map[string]string{
+				"Content-Type": models.MimeTypeEnrichedPersonDataV1,
+				"ETag":         `"2"`,
+			},
+			wantBody: &models.EnrichedPersonDataV1{
+				Id:      101,
+				Surname: "Ivanov",
+				Name:    "Ivan",
+				Country: "RU",
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "Point-in-time data of deleted person requires deletion scope (403)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					r := httptest.NewRequest("GET", "/v1/people/{id}?as_of=2023-09-01T12:00:00Z", nil)
+					ctx := chi.NewRouteContext()
+					ctx.URLParams.Add("id", "101")
+					return r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, ctx))
+				}(),
+			},
+			testService: testService{
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("HistoryStart", mock.Anything).Return(time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC), nil)
+					s.On("EnrichedPersonDataV1AsOf", mock.Anything, int64(101), mock.Anything).Return(
+						&models.EnrichedPersonDataV1{Id: 101, Surname: "Ivanov", Name: "Ivan", Version: 2}, nil)
+					s.On("PersonDataExists", mock.Anything, int64(101)).Return(false, nil)
+					return s
+				}(),
+			},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name: "Person not existed at the moment (404)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					r := httptest.NewRequest("GET", "/v1/people/{id}?as_of=2020-01-01", nil)
+					ctx := chi.NewRouteContext()
+					ctx.URLParams.Add("id", "101")
+					return r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, ctx))
+				}(),
+			},
+			testService: testService{
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("HistoryStart", mock.Anything).Return(time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC), nil)
+					s.On("EnrichedPersonDataV1AsOf", mock.Anything, int64(101), time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)).
+						Return(nil, nil)
+					return s
+				}(),
+			},
+			wantHeaders: map[string]string{},
+			wantStatus:  http.StatusNotFound,
+		},
+		{
+			name: "Incorrect point in time (400)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					r := httptest.NewRequest("GET", "/v1/people/{id}?as_of=yesterday", nil)
+					ctx := chi.NewRouteContext()
+					ctx.URLParams.Add("id", "101")
+					return r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, ctx))
+				}(),
+			},
+			wantHeaders: map[string]string{},
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			name: "Expanded country (200)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					r := httptest.NewRequest("GET", "/v1/people/{id}?expand=country", nil)
+					ctx := chi.NewRouteContext()
+					ctx.URLParams.Add("id", "101")
+					return r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, ctx))
+				}(),
+			},
+			testService: testService{
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("EnrichedPersonDataV1", mock.Anything, int64(101)).Return(
+						&models.EnrichedPersonDataV1{Id: 101, Surname: "Ivanov", Name: "Ivan", Country: "RU"}, nil)
+					return s
+				}(),
+			},
+			wantHeaders: map[string]string{
+				"Content-Type": models.MimeTypeEnrichedPersonDataV1,
+			},
+			wantBody: &models.EnrichedPersonDataV1{
+				Id:      101,
+				Surname: "Ivanov",
+				Name:    "Ivan",
+				Country: "RU",
+				CountryDetails: &models.CountryV1{
+					Code:   "RU",
+					Names:  models.CountryNamesV1{En: "Russian Federation", Ru: "Россия"},
+					Region: "Europe",
+				},
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "Unsupported expansion (400)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					r := httptest.NewRequest("GET", "/v1/people/{id}?expand=gender", nil)
+					ctx := chi.NewRouteContext()
+					ctx.URLParams.Add("id", "101")
+					return r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, ctx))
+				}(),
+			},
+			wantHeaders: map[string]string{},
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			name: "Not modified (304)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					r := httptest.NewRequest("GET", "/v1/people/{id}", nil)
+					r.Header.Set("If-None-Match", `"3", W/"4"`)
+					ctx := chi.NewRouteContext()
+					ctx.URLParams.Add("id", "101")
+					return r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, ctx))
+				}(),
+			},
+			testService: testService{
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("EnrichedPersonDataV1", mock.Anything, int64(101)).Return(
+						&models.EnrichedPersonDataV1{Id: 101, Surname: "Ivanov", Name: "Ivan", Version: 4}, nil)
+					return s
+				}(),
+			},
+			wantHeaders: map[string]string{
+				"ETag": `"4"`,
+			},
+			wantStatus: http.StatusNotModified,
+		},
 		{
 			name: "Person not found in DB (404)",
 			args: args{
@@ -108,6 +278,57 @@ func TestService_getPersonData(t *testing.T) {
 			wantHeaders: map[string]string{},
 			wantStatus:  http.StatusNotFound,
 		},
+		{
+			name: "Generic JSON is acceptable (200)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					r := httptest.NewRequest("GET", "/v1/people/{id}", nil)
+					r.Header.Set("Accept", "application/json, */*;q=0.8")
+					ctx := chi.NewRouteContext()
+					ctx.URLParams.Add("id", "101")
+					return r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, ctx))
+				}(),
+			},
+			testService: testService{
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("EnrichedPersonDataV1", mock.Anything, int64(101)).Return(
+						&models.EnrichedPersonDataV1{Id: 101, Surname: "Ivanov", Name: "Ivan", Version: 1}, nil)
+					return s
+				}(),
+			},
+			wantHeaders: map[string]string{
+				"Content-Type": models.MimeTypeEnrichedPersonDataV1,
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "Version 2 requested (200)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					r := httptest.NewRequest("GET", "/v1/people/{id}", nil)
+					r.Header.Set("Accept", models.MimeTypeEnrichedPersonDataV2)
+					ctx := chi.NewRouteContext()
+					ctx.URLParams.Add("id", "101")
+					return r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, ctx))
+				}(),
+			},
+			testService: testService{
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("EnrichedPersonDataV2", mock.Anything, int64(101)).Return(
+						&models.EnrichedPersonDataV2{Id: 101, Surname: "Ivanov", Name: "Ivan", Tags: []string{"test"}, Version: 2}, nil)
+					return s
+				}(),
+			},
+			wantHeaders: map[string]string{
+				"Content-Type": models.MimeTypeEnrichedPersonDataV2,
+				"ETag":         `"2"`,
+			},
+			wantStatus: http.StatusOK,
+		},
 		{
 			name: "Requested media type is not supported (406)",
 			args: args{
@@ -138,7 +359,7 @@ func TestService_getPersonData(t *testing.T) {
 					if h == nil {
 						return ""
 					}
-					v := h[k]
+					v := h.Values(k)
 					if len(v) == 0 {
 						return ""
 					}
@@ -0,0 +1,200 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/barpav/demography/internal/actor"
+	"github.com/barpav/demography/internal/rest/models"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	maxIdempotencyKeyLength      = 255
+	maxIdempotentRequestSize     = 1 << 20 // bytes
+	idempotencyKeyLock           = time.Minute
+	idempotencyCompletionTimeout = 5 * time.Second
+)
+
+// Replays original response to retries of request with the same Idempotency-Key header.
+// Keys are scoped per client (see Service.authenticate).
+func (s *Service) idempotent(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get("Idempotency-Key")
+
+		if key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if len(key) > maxIdempotencyKeyLength {
+			respondWithProblemDetail(w, http.StatusBadRequest,
+				fmt.Sprintf("Idempotency-Key must not exceed %d characters.", maxIdempotencyKeyLength))
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxIdempotentRequestSize))
+
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+
+			if errors.As(err, &tooLarge) {
+				respondWithProblemDetail(w, http.StatusRequestEntityTooLarge,
+					fmt.Sprintf("Request with Idempotency-Key must not exceed %d bytes.", maxIdempotentRequestSize))
+				return
+			}
+
+			respondWithProblemDetail(w, http.StatusBadRequest, "Failed to read request body.")
+			return
+		}
+
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		fingerprint := requestFingerprint(r, body)
+
+		client := actor.FromContext(r.Context())
+		reservation := make([]byte, 16) // distinguishes the request from the ones taking over the key after lock
+		_, err = rand.Read(reservation)
+
+		var original *models.IdempotentResponse
+		if err == nil {
+			original, err = s.storage.ReserveIdempotencyKey(r.Context(), client, key, reservation, fingerprint,
+				idempotencyKeyLock, s.cfg.idempotencyKeyTTL)
+		}
+
+		if err != nil {
+			log.Err(err).Msg("Failed to reserve idempotency key.")
+			respondWithProblem(w, http.StatusInternalServerError)
+			return
+		}
+
+		if original != nil {
+			replayIdempotentResponse(w, original, fingerprint)
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: w}
+
+		defer func() {
+			if p := recover(); p != nil {
+				s.completeIdempotentRequest(client, key, reservation, nil)
+				panic(p)
+			}
+		}()
+
+		next.ServeHTTP(recorder, r)
+
+		if recorder.status >= http.StatusInternalServerError {
+			s.completeIdempotentRequest(client, key, reservation, nil)
+		} else {
+			s.completeIdempotentRequest(client, key, reservation, recorder.response())
+		}
+	})
+}
+
+// Response is saved for replays, or key is released for retries if there is no response (request failed).
+// Must be done even if client is gone.
+func (s *Service) completeIdempotentRequest(client, key string, reservation []byte, response *models.IdempotentResponse) {
+	ctx, cancel := context.WithTimeout(context.Background(), idempotencyCompletionTimeout)
+	defer cancel()
+
+	var err error
+
+	if response == nil {
+		err = s.storage.ReleaseIdempotencyKey(ctx, client, key, reservation)
+	} else {
+		err = s.storage.SaveIdempotentResponse(ctx, client, key, reservation, response)
+	}
+
+	if err != nil {
+		log.Err(err).Msg("Failed to complete idempotent request.")
+	}
+}
+
+func replayIdempotentResponse(w http.ResponseWriter, original *models.IdempotentResponse, fingerprint []byte) {
+	if !bytes.Equal(original.Fingerprint, fingerprint) {
+		writeProblem(w, &models.Problem{
+			Type:   models.ProblemTypeIdempotencyKeyReuse,
+			Title:  "Idempotency-Key has already been used for a different request.",
+			Status: http.StatusUnprocessableEntity,
+		})
+		return
+	}
+
+	if !original.Completed {
+		w.Header().Set("Retry-After", "1")
+		writeProblem(w, &models.Problem{
+			Type:   models.ProblemTypeIdempotencyKeyInUse,
+			Title:  "Request with the same Idempotency-Key is being processed.",
+			Status: http.StatusConflict,
+		})
+		return
+	}
+
+	for k, v := range original.Header {
+		w.Header()[k] = v
+	}
+
+	w.Header().Set("Idempotent-Replayed", "true")
+	w.WriteHeader(original.StatusCode)
+	_, err := w.Write(original.Body)
+
+	if err != nil {
+		log.Err(err).Msg("Failed to replay idempotent response.")
+		return
+	}
+
+	log.Info().Msg("Idempotent response replayed.")
+}
+
+// The same key can't be used for different requests.
+func requestFingerprint(r *http.Request, body []byte) []byte {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s %s\n%s\n", r.Method, r.URL.Path, r.Header.Get("Content-Type"))
+	h.Write(body)
+	return h.Sum(nil)
+}
+
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	header http.Header // at the moment of writing status
+	body   bytes.Buffer
+}
+
+func (rr *responseRecorder) WriteHeader(status int) {
+	if rr.status == 0 {
+		rr.status = status
+		rr.header = rr.Header().Clone()
+	}
+
+	rr.ResponseWriter.WriteHeader(status)
+}
+
+func (rr *responseRecorder) Write(b []byte) (int, error) {
+	if rr.status == 0 {
+		rr.WriteHeader(http.StatusOK)
+	}
+
+	rr.body.Write(b)
+	return rr.ResponseWriter.Write(b)
+}
+
+func (rr *responseRecorder) response() *models.IdempotentResponse {
+	if rr.status == 0 {
+		rr.status, rr.header = http.StatusOK, rr.Header().Clone()
+	}
+
+	return &models.IdempotentResponse{
+		Completed:  true,
+		StatusCode: rr.status,
+		Header:     rr.header,
+		Body:       rr.body.Bytes(),
+	}
+}
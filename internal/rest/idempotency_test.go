@@ -0,0 +1,241 @@
+package rest
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/barpav/demography/internal/actor"
+	"github.com/barpav/demography/internal/rest/mocks"
+	"github.com/barpav/demography/internal/rest/models"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_idempotent(t *testing.T) {
+	cfg := &config{idempotencyKeyTTL: time.Hour}
+	request := func(key, body string) *http.Request {
+		r := httptest.NewRequest("POST", "/v1/people", strings.NewReader(body))
+		r.Header.Set("Content-Type", models.MimeTypeNewPersonDataV1)
+		if key != "" {
+			r.Header.Set("Idempotency-Key", key)
+		}
+		return r.WithContext(actor.NewContext(r.Context(), "api-key:reporting"))
+	}
+	fingerprint := requestFingerprint(request("", `{"name": "Ivan"}`), []byte(`{"name": "Ivan"}`))
+	created := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", models.MimeTypeEnrichedPersonDataV1)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id": 1}`))
+	}
+	failed := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+
+	type testService struct {
+		storage Storage
+	}
+	type args struct {
+		w *httptest.ResponseRecorder
+		r *http.Request
+	}
+	tests := []struct {
+		name        string
+		testService testService
+		handler     http.HandlerFunc
+		args        args
+		wantHeaders map[string]string
+		wantBody    string
+		wantStatus  int
+		wantPanic   bool
+	}{
+		{
+			name:    "Without key (201)",
+			handler: created,
+			args: args{
+				w: httptest.NewRecorder(),
+				r: request("", `{"name": "Ivan"}`),
+			},
+			wantBody:   `{"id": 1}`,
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:    "First request saved (201)",
+			handler: created,
+			args: args{
+				w: httptest.NewRecorder(),
+				r: request("key-1", `{"name": "Ivan"}`),
+			},
+			testService: testService{
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					var reservation []byte
+					s.On("ReserveIdempotencyKey", mock.Anything, "api-key:reporting", "key-1", mock.Anything, fingerprint, idempotencyKeyLock, time.Hour).
+						Run(func(args mock.Arguments) { reservation = args.Get(3).([]byte) }).Return(nil, nil)
+					s.On("SaveIdempotentResponse", mock.Anything, "api-key:reporting", "key-1",
+						mock.MatchedBy(func(r []byte) bool { return len(r) != 0 && bytes.Equal(r, reservation) }),
+						mock.MatchedBy(func(r *models.IdempotentResponse) bool {
+							return r.Completed && r.StatusCode == http.StatusCreated && string(r.Body) == `{"id": 1}` &&
+								r.Header["Content-Type"][0] == models.MimeTypeEnrichedPersonDataV1
+						}),
+					).Return(nil)
+					return s
+				}(),
+			},
+			wantBody:   `{"id": 1}`,
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:    "Failed request releases key (500)",
+			handler: failed,
+			args: args{
+				w: httptest.NewRecorder(),
+				r: request("key-1", `{"name": "Ivan"}`),
+			},
+			testService: testService{
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					var reservation []byte
+					s.On("ReserveIdempotencyKey", mock.Anything, "api-key:reporting", "key-1", mock.Anything, fingerprint, idempotencyKeyLock, time.Hour).
+						Run(func(args mock.Arguments) { reservation = args.Get(3).([]byte) }).Return(nil, nil)
+					s.On("ReleaseIdempotencyKey", mock.Anything, "api-key:reporting", "key-1",
+						mock.MatchedBy(func(r []byte) bool { return len(r) != 0 && bytes.Equal(r, reservation) })).Return(nil)
+					return s
+				}(),
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+		{
+			name:    "Original response replayed (201)",
+			handler: failed,
+			args: args{
+				w: httptest.NewRecorder(),
+				r: request("key-1", `{"name": "Ivan"}`),
+			},
+			testService: testService{
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("ReserveIdempotencyKey", mock.Anything, "api-key:reporting", "key-1", mock.Anything, fingerprint, idempotencyKeyLock, time.Hour).Return(
+						&models.IdempotentResponse{
+							Fingerprint: fingerprint,
+							Completed:   true,
+							StatusCode:  http.StatusCreated,
+							Header:      map[string][]string{"Content-Type": {models.MimeTypeEnrichedPersonDataV1}},
+							Body:        []byte(`{"id": 1}`),
+						}, nil)
+					return s
+				}(),
+			},
+			wantHeaders: map[string]string{
+				"Content-Type":        models.MimeTypeEnrichedPersonDataV1,
+				"Idempotent-Replayed": "true",
+			},
+			wantBody:   `{"id": 1}`,
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:    "Original request in progress (409)",
+			handler: created,
+			args: args{
+				w: httptest.NewRecorder(),
+				r: request("key-1", `{"name": "Ivan"}`),
+			},
+			testService: testService{
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("ReserveIdempotencyKey", mock.Anything, "api-key:reporting", "key-1", mock.Anything, fingerprint, idempotencyKeyLock, time.Hour).Return(
+						&models.IdempotentResponse{Fingerprint: fingerprint}, nil)
+					return s
+				}(),
+			},
+			wantHeaders: map[string]string{
+				"Retry-After": "1",
+			},
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name:    "Key reused for different request (422)",
+			handler: created,
+			args: args{
+				w: httptest.NewRecorder(),
+				r: request("key-1", `{"name": "Petr"}`),
+			},
+			testService: testService{
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("ReserveIdempotencyKey", mock.Anything, "api-key:reporting", "key-1", mock.Anything, mock.Anything, idempotencyKeyLock, time.Hour).Return(
+						&models.IdempotentResponse{Fingerprint: fingerprint, Completed: true}, nil)
+					return s
+				}(),
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:    "Handler panicked",
+			handler: func(w http.ResponseWriter, r *http.Request) { panic(http.ErrAbortHandler) },
+			args: args{
+				w: httptest.NewRecorder(),
+				r: request("key-1", `{"name": "Ivan"}`),
+			},
+			testService: testService{
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					var reservation []byte
+					s.On("ReserveIdempotencyKey", mock.Anything, "api-key:reporting", "key-1", mock.Anything, fingerprint, idempotencyKeyLock, time.Hour).
+						Run(func(args mock.Arguments) { reservation = args.Get(3).([]byte) }).Return(nil, nil)
+					s.On("ReleaseIdempotencyKey", mock.Anything, "api-key:reporting", "key-1",
+						mock.MatchedBy(func(r []byte) bool { return len(r) != 0 && bytes.Equal(r, reservation) })).Return(nil)
+					return s
+				}(),
+			},
+			wantPanic: true,
+		},
+		{
+			name:    "Request is too large (413)",
+			handler: created,
+			args: args{
+				w: httptest.NewRecorder(),
+				r: request("key-1", strings.Repeat(" ", maxIdempotentRequestSize+1)),
+			},
+			wantStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:    "Key is too long (400)",
+			handler: created,
+			args: args{
+				w: httptest.NewRecorder(),
+				r: request(strings.Repeat("k", maxIdempotencyKeyLength+1), `{"name": "Ivan"}`),
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Service{
+				storage: tt.testService.storage,
+				cfg:     cfg,
+			}
+
+			if tt.wantPanic {
+				require.Panics(t, func() { s.idempotent(tt.handler).ServeHTTP(tt.args.w, tt.args.r) })
+				return
+			}
+
+			s.idempotent(tt.handler).ServeHTTP(tt.args.w, tt.args.r)
+
+			require.Equal(t, tt.wantStatus, tt.args.w.Code)
+
+			for k, v := range tt.wantHeaders {
+				require.Equal(t, v, tt.args.w.Result().Header.Get(k))
+			}
+
+			if tt.wantBody != "" {
+				require.Equal(t, tt.wantBody, tt.args.w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,376 @@
+package rest
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/barpav/demography/internal/rest/models"
+	"github.com/barpav/demography/internal/rest/negotiation"
+	"github.com/rs/zerolog/log"
+)
+
+// https://barpav.github.io/demography-api/#/people/post_people_import
+func (s *Service) importPeople(w http.ResponseWriter, r *http.Request) {
+	switch negotiation.ContentType(r.Header.Get("Content-Type"), models.MimeTypeCSV, models.MimeTypeNDJSON) {
+	case models.MimeTypeCSV:
+		s.importPeopleV1(w, r, &csvPeopleReader{records: csv.NewReader(r.Body)})
+	case models.MimeTypeNDJSON:
+		s.importPeopleV1(w, r, newNDJSONPeopleReader(r.Body))
+	default:
+		respondWithProblem(w, http.StatusUnsupportedMediaType)
+		return
+	}
+}
+
+// Records are validated one by one as they are read, then enriched and saved in batches,
+// so the whole imported data is never kept in memory.
+func (s *Service) importPeopleV1(w http.ResponseWriter, r *http.Request, reader newPeopleReader) {
+	ctx := r.Context()
+	report := &models.ImportReportV1{Rows: make([]*models.ImportedRowV1, 0)}
+	batch := make([]*importedPerson, 0, s.cfg.importBatchSize)
+	var limited bool // by records rate limit
+	var err error
+
+	// rows exceeding rate limit are still read to be reported
+	save := func() error {
+		if !limited && s.takeRecords(w, r, len(batch)) {
+			return s.importBatchV1(ctx, batch)
+		}
+
+		limited = true
+
+		for _, person := range batch {
+			person.row.Errors = []string{"Rate limit exceeded."}
+		}
+
+		return nil
+	}
+
+	for {
+		var data *models.NewPersonDataV1
+		data, err = reader.Read()
+
+		if err == io.EOF {
+			err = nil
+			break
+		}
+
+		row := &models.ImportedRowV1{Row: reader.Row()}
+
+		if err != nil {
+			var invalid errInvalidRecord
+
+			if !errors.As(err, &invalid) {
+				// rows read so far are still imported
+				report.Error = fmt.Sprintf("Failed to read import data: %s", err)
+				err = nil
+				break
+			}
+
+			row.Errors = errorMessages(invalid.err)
+			report.Rows = append(report.Rows, row)
+			continue
+		}
+
+		report.Rows = append(report.Rows, row)
+		batch = append(batch, &importedPerson{row: row, data: data})
+
+		if len(batch) == s.cfg.importBatchSize {
+			err = save()
+			batch = batch[:0]
+
+			if err != nil {
+				break
+			}
+		}
+	}
+
+	if err == nil && len(batch) > 0 {
+		err = save()
+	}
+
+	if limited && report.Error == "" {
+		report.Error = rateLimitExceeded(s.recordLimits, "records")
+	}
+
+	if err != nil {
+		log.Err(err).Msg("Failed to import people data (v1).")
+
+		if report.Error == "" {
+			report.Error = "Failed to save people data."
+		}
+	}
+
+	if report.Error != "" && len(report.Rows) == 0 {
+		respondWithProblemDetail(w, http.StatusBadRequest, report.Error)
+		return
+	}
+
+	for _, row := range report.Rows {
+		if row.Id != 0 {
+			report.Created++
+		} else {
+			report.Failed++
+		}
+	}
+
+	if limited && report.Created == 0 {
+		respondWithRateLimitExceeded(w, s.recordLimits, "records")
+		return
+	}
+
+	w.Header().Set("Content-Type", models.MimeTypeImportReportV1)
+	err = json.NewEncoder(w).Encode(report)
+
+	if err != nil {
+		log.Err(err).Msg("Failed to serialize import report (v1).")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	log.Info().Msg(fmt.Sprintf("People data imported: %d created, %d failed.", report.Created, report.Failed))
+}
+
+type importedPerson struct {
+	row  *models.ImportedRowV1
+	data *models.NewPersonDataV1
+}
+
+func (s *Service) importBatchV1(ctx context.Context, batch []*importedPerson) error {
+	data := make([]*models.NewPersonDataV1, 0, len(batch))
+
+	for _, person := range batch {
+		data = append(data, person.data)
+	}
+
+	enriched, errs := s.enrichedPeopleDataV1(ctx, data)
+
+	for i, err := range errs {
+		if err != nil {
+			log.Err(err).Msg(fmt.Sprintf("Failed to receive enriched person data (v1) for row %d.", batch[i].row.Row))
+			batch[i].row.Errors = []string{"Failed to enrich person data."}
+		}
+	}
+
+	toSave := make([]*models.EnrichedPersonDataV1, 0, len(batch))
+	rows := make([]*models.ImportedRowV1, 0, len(batch))
+
+	for i, data := range enriched {
+		if data != nil {
+			toSave = append(toSave, data)
+			rows = append(rows, batch[i].row)
+		}
+	}
+
+	err := s.storage.ImportPeopleDataV1(ctx, toSave)
+
+	// violating rows are found by saving them one by one
+	if _, ok := err.(ErrPersonDataConstraintViolation); ok {
+		return s.importRowsV1(ctx, toSave, rows)
+	}
+
+	if err != nil {
+		for _, row := range rows {
+			row.Errors = []string{"Failed to save person data."}
+		}
+
+		return err
+	}
+
+	for i, data := range toSave {
+		rows[i].Id = data.Id
+	}
+
+	return nil
+}
+
+func (s *Service) importRowsV1(ctx context.Context, data []*models.EnrichedPersonDataV1, rows []*models.ImportedRowV1) error {
+	for i, person := range data {
+		err := s.storage.CreateNewPersonDataV1(ctx, person)
+
+		if violation, ok := err.(ErrPersonDataConstraintViolation); ok {
+			_, reason := violation.InvalidField()
+			rows[i].Errors = []string{reason}
+			continue
+		}
+
+		if err != nil {
+			for _, row := range rows[i:] {
+				row.Errors = []string{"Failed to save person data."}
+			}
+
+			return err
+		}
+
+		rows[i].Id = person.Id
+	}
+
+	return nil
+}
+
+// Returns io.EOF if there is no more data. Errors of type errInvalidRecord don't interrupt reading.
+type newPeopleReader interface {
+	Read() (*models.NewPersonDataV1, error)
+	Row() int // number of the last read record in imported data
+}
+
+type errInvalidRecord struct {
+	err error
+}
+
+func (e errInvalidRecord) Error() string {
+	return e.err.Error()
+}
+
+// CSV header is mandatory and must contain 'surname' and 'name' columns, 'patronymic' is optional.
+type csvPeopleReader struct {
+	records *csv.Reader
+	columns []int // surname, name, patronymic (-1 if absent)
+	row     int   // header is not counted
+}
+
+func (c *csvPeopleReader) Read() (*models.NewPersonDataV1, error) {
+	if c.columns == nil {
+		err := c.readHeader()
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	record, err := c.records.Read()
+
+	if err != io.EOF {
+		c.row++
+	}
+
+	if err != nil {
+		var parseErr *csv.ParseError
+
+		if errors.As(err, &parseErr) {
+			return nil, errInvalidRecord{err}
+		}
+
+		return nil, err
+	}
+
+	fields := make([]string, len(c.columns))
+
+	for i, column := range c.columns {
+		if column >= 0 && column < len(record) {
+			fields[i] = record[column]
+		}
+	}
+
+	data := &models.NewPersonDataV1{}
+	err = data.DeserializeRecord(fields)
+
+	if err != nil {
+		return nil, errInvalidRecord{err}
+	}
+
+	return data, nil
+}
+
+func (c *csvPeopleReader) Row() int {
+	return c.row
+}
+
+func (c *csvPeopleReader) readHeader() error {
+	c.records.FieldsPerRecord = -1
+	header, err := c.records.Read()
+
+	if err == io.EOF {
+		return errors.New("CSV header is missing")
+	}
+
+	if err != nil {
+		return err
+	}
+
+	c.columns = []int{-1, -1, -1}
+
+	for i, column := range header {
+		switch strings.ToLower(strings.TrimSpace(column)) {
+		case "surname":
+			c.columns[0] = i
+		case "name":
+			c.columns[1] = i
+		case "patronymic":
+			c.columns[2] = i
+		}
+	}
+
+	if c.columns[0] < 0 || c.columns[1] < 0 {
+		return errors.New("CSV header must contain 'surname' and 'name' columns")
+	}
+
+	return nil
+}
+
+// Empty lines are skipped, but counted as rows to match line numbers.
+type ndjsonPeopleReader struct {
+	lines *bufio.Scanner
+	row   int
+}
+
+func newNDJSONPeopleReader(data io.Reader) *ndjsonPeopleReader {
+	const maxLineSize = 64 * 1024
+	lines := bufio.NewScanner(data)
+	lines.Buffer(make([]byte, 0, 4096), maxLineSize)
+	return &ndjsonPeopleReader{lines: lines}
+}
+
+func (n *ndjsonPeopleReader) Read() (*models.NewPersonDataV1, error) {
+	for n.lines.Scan() {
+		n.row++
+		line := strings.TrimSpace(n.lines.Text())
+
+		if line == "" {
+			continue
+		}
+
+		data := &models.NewPersonDataV1{}
+		err := data.Deserialize(strings.NewReader(line))
+
+		if err != nil {
+			return nil, errInvalidRecord{err}
+		}
+
+		return data, nil
+	}
+
+	err := n.lines.Err()
+
+	if err == nil {
+		err = io.EOF
+	}
+
+	return nil, err
+}
+
+func (n *ndjsonPeopleReader) Row() int {
+	return n.row
+}
+
+// Splits joined errors (e.g. validation errors) into separate messages.
+func errorMessages(err error) []string {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		messages := make([]string, 0, len(joined.Unwrap()))
+
+		for _, e := range joined.Unwrap() {
+			messages = append(messages, e.Error())
+		}
+
+		return messages
+	}
+
+	return []string{err.Error()}
+}
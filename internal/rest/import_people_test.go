@@ -0,0 +1,351 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/barpav/demography/internal/rest/mocks"
+	"github.com/barpav/demography/internal/rest/models"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_importPeople(t *testing.T) {
+	statistics := func() *mocks.StatisticsProvider {
+		s := mocks.NewStatisticsProvider(t)
+		s.On("AgeByName", mock.Anything).Return(50, nil)
+		s.On("GenderByName", mock.Anything).Return("male", nil)
+		s.On("CountryByName", mock.Anything).Return("RU", nil)
+		return s
+	}
+
+	// assigns sequential ids starting from 'first'
+	saved := func(first int64) func(args mock.Arguments) {
+		return func(args mock.Arguments) {
+			for i, data := range args.Get(1).([]*models.EnrichedPersonDataV1) {
+				data.Id = first + int64(i)
+			}
+		}
+	}
+
+	type testService struct {
+		cfg          *config
+		stats        StatisticsProvider
+		storage      Storage
+		recordLimits *rateLimiter
+	}
+	type args struct {
+		w *httptest.ResponseRecorder
+		r *http.Request
+	}
+	tests := []struct {
+		name        string
+		testService testService
+		args        args
+		wantHeaders map[string]string
+		wantBody    *models.ImportReportV1
+		wantStatus  int
+	}{
+		{
+			name: "CSV imported in batches (200)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					data := "name,surname,patronymic\n" +
+						"Ivan,Ivanov,Ivanovich\n" +
+						"Petr,,\n" +
+						"Petr,Petrov\n" +
+						"Lev,Tolstoy,Nikolaevich\n"
+					r := httptest.NewRequest("POST", "/v1/people:import", strings.NewReader(data))
+					r.Header.Set("Content-Type", models.MimeTypeCSV)
+					return r
+				}(),
+			},
+			testService: testService{
+				cfg:   &config{statsTimeout: 3000, importBatchSize: 2, enrichmentConcurrency: 2},
+				stats: statistics(),
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("ImportPeopleDataV1", mock.Anything, mock.MatchedBy(func(data []*models.EnrichedPersonDataV1) bool {
+						return len(data) == 2 && data[0].Surname == "Ivanov" && data[1].Surname == "Petrov"
+					})).Run(saved(1)).Return(nil).Once()
+					s.On("ImportPeopleDataV1", mock.Anything, mock.MatchedBy(func(data []*models.EnrichedPersonDataV1) bool {
+						return len(data) == 1 && data[0].Surname == "Tolstoy" && data[0].Country == "RU"
+					})).Run(saved(3)).Return(nil).Once()
+					return s
+				}(),
+			},
+			wantHeaders: map[string]string{
+				"Content-Type": models.MimeTypeImportReportV1,
+			},
+			wantBody: &models.ImportReportV1{
+				Created: 3,
+				Failed:  1,
+				Rows: []*models.ImportedRowV1{
+					{Row: 1, Id: 1},
+					{Row: 2, Errors: []string{"Person's surname must be specified."}},
+					{Row: 3, Id: 2},
+					{Row: 4, Id: 3},
+				},
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "CSV imported partially due to records rate limit (200)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					data := "name,surname\n" +
+						"Ivan,Ivanov\n" +
+						"Petr,Petrov\n" +
+						"Lev,Tolstoy\n" +
+						"Anna,\n" +
+						"Anna,Karenina\n"
+					r := httptest.NewRequest("POST", "/v1/people:import", strings.NewReader(data))
+					r.Header.Set("Content-Type", models.MimeTypeCSV)
+					return r
+				}(),
+			},
+			testService: testService{
+				cfg:          &config{statsTimeout: 3000, importBatchSize: 2, enrichmentConcurrency: 2},
+				stats:        statistics(),
+				recordLimits: newRateLimiter(2),
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("ImportPeopleDataV1", mock.Anything, mock.Anything).Run(saved(1)).Return(nil).Once()
+					return s
+				}(),
+			},
+			wantHeaders: map[string]string{
+				"Content-Type": models.MimeTypeImportReportV1,
+				"Retry-After":  "60",
+			},
+			wantBody: &models.ImportReportV1{
+				Created: 2,
+				Failed:  3,
+				Error:   "Rate limit of 2 records per minute exceeded.",
+				Rows: []*models.ImportedRowV1{
+					{Row: 1, Id: 1},
+					{Row: 2, Id: 2},
+					{Row: 3, Errors: []string{"Rate limit exceeded."}},
+					{Row: 4, Errors: []string{"Person's surname must be specified."}},
+					{Row: 5, Errors: []string{"Rate limit exceeded."}},
+				},
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "Nothing imported due to records rate limit (429)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					r := httptest.NewRequest("POST", "/v1/people:import", strings.NewReader("name,surname\nIvan,Ivanov\nPetr,Petrov\n"))
+					r.Header.Set("Content-Type", models.MimeTypeCSV)
+					return r
+				}(),
+			},
+			testService: testService{
+				cfg:          &config{statsTimeout: 3000, importBatchSize: 10, enrichmentConcurrency: 2},
+				recordLimits: newRateLimiter(1),
+			},
+			wantHeaders: map[string]string{
+				"Retry-After": "60",
+			},
+			wantStatus: http.StatusTooManyRequests,
+		},
+		{
+			name: "NDJSON imported with saving error (200)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					data := `{"surname": "Ivanov", "name": "Ivan"}` + "\n\n" +
+						`{"surname": "Petrov"` + "\n" +
+						`{"surname": "Sidorov", "name": "Ivan"}` + "\n"
+					r := httptest.NewRequest("POST", "/v1/people:import", strings.NewReader(data))
+					r.Header.Set("Content-Type", models.MimeTypeNDJSON)
+					return r
+				}(),
+			},
+			testService: testService{
+				cfg:   &config{statsTimeout: 3000, importBatchSize: 10, enrichmentConcurrency: 5},
+				stats: statistics(),
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("ImportPeopleDataV1", mock.Anything, mock.Anything).Return(errors.New("test"))
+					return s
+				}(),
+			},
+			wantHeaders: map[string]string{
+				"Content-Type": models.MimeTypeImportReportV1,
+			},
+			wantBody: &models.ImportReportV1{
+				Failed: 3,
+				Error:  "Failed to save people data.",
+				Rows: []*models.ImportedRowV1{
+					{Row: 1, Errors: []string{"Failed to save person data."}},
+					{Row: 3, Errors: []string{"New person data violates 'newPersonData.v1' schema."}},
+					{Row: 4, Errors: []string{"Failed to save person data."}},
+				},
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "NDJSON imported with constraint violation (200)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					data := `{"surname": "Ivanov", "name": "Ivan"}` + "\n" +
+						`{"surname": "Sidorov", "name": "Ivan"}` + "\n"
+					r := httptest.NewRequest("POST", "/v1/people:import", strings.NewReader(data))
+					r.Header.Set("Content-Type", models.MimeTypeNDJSON)
+					return r
+				}(),
+			},
+			testService: testService{
+				cfg:   &config{statsTimeout: 3000, importBatchSize: 10, enrichmentConcurrency: 5},
+				stats: statistics(),
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("ImportPeopleDataV1", mock.Anything, mock.Anything).Return(ErrPersonDataConstraintViolationTest{})
+					s.On("CreateNewPersonDataV1", mock.Anything, mock.MatchedBy(func(data *models.EnrichedPersonDataV1) bool {
+						return data.Surname == "Ivanov"
+					})).Run(func(args mock.Arguments) {
+						args.Get(1).(*models.EnrichedPersonDataV1).Id = 1
+					}).Return(nil)
+					s.On("CreateNewPersonDataV1", mock.Anything, mock.MatchedBy(func(data *models.EnrichedPersonDataV1) bool {
+						return data.Surname == "Sidorov"
+					})).Return(ErrPersonDataConstraintViolationTest{})
+					return s
+				}(),
+			},
+			wantHeaders: map[string]string{
+				"Content-Type": models.MimeTypeImportReportV1,
+			},
+			wantBody: &models.ImportReportV1{
+				Created: 1,
+				Failed:  1,
+				Rows: []*models.ImportedRowV1{
+					{Row: 1, Id: 1},
+					{Row: 2, Errors: []string{"Person's age must be between 0 and 150."}},
+				},
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "NDJSON read partially (200)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					data := `{"surname": "Ivanov", "name": "Ivan"}` + "\n" +
+						`{"surname": "` + strings.Repeat("a", 70*1024) + `"}` + "\n" +
+						`{"surname": "Sidorov", "name": "Ivan"}` + "\n"
+					r := httptest.NewRequest("POST", "/v1/people:import", strings.NewReader(data))
+					r.Header.Set("Content-Type", models.MimeTypeNDJSON)
+					return r
+				}(),
+			},
+			testService: testService{
+				cfg:   &config{statsTimeout: 3000, importBatchSize: 10, enrichmentConcurrency: 5},
+				stats: statistics(),
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("ImportPeopleDataV1", mock.Anything, mock.MatchedBy(func(data []*models.EnrichedPersonDataV1) bool {
+						return len(data) == 1 && data[0].Surname == "Ivanov"
+					})).Run(saved(1)).Return(nil).Once()
+					return s
+				}(),
+			},
+			wantHeaders: map[string]string{
+				"Content-Type": models.MimeTypeImportReportV1,
+			},
+			wantBody: &models.ImportReportV1{
+				Created: 1,
+				Error:   "Failed to read import data: bufio.Scanner: token too long",
+				Rows: []*models.ImportedRowV1{
+					{Row: 1, Id: 1},
+				},
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "CSV header is missing (400)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					r := httptest.NewRequest("POST", "/v1/people:import", strings.NewReader("Ivanov,Ivan\n"))
+					r.Header.Set("Content-Type", models.MimeTypeCSV)
+					return r
+				}(),
+			},
+			testService: testService{
+				cfg: &config{statsTimeout: 3000, importBatchSize: 10, enrichmentConcurrency: 5},
+			},
+			wantHeaders: map[string]string{},
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			name: "Unsupported import data (415)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					r := httptest.NewRequest("POST", "/v1/people:import", strings.NewReader("[]"))
+					r.Header.Set("Content-Type", "application/json")
+					return r
+				}(),
+			},
+			wantHeaders: map[string]string{},
+			wantStatus:  http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Service{
+				cfg:          tt.testService.cfg,
+				stats:        tt.testService.stats,
+				storage:      tt.testService.storage,
+				recordLimits: tt.testService.recordLimits,
+			}
+			s.importPeople(tt.args.w, tt.args.r)
+
+			for k, v := range tt.wantHeaders {
+				require.Equal(t, v, func() string {
+					h := tt.args.w.Result().Header
+					if h == nil {
+						return ""
+					}
+					v := h[k]
+					if len(v) == 0 {
+						return ""
+					}
+					return v[0]
+				}())
+			}
+
+			require.Equal(t, tt.wantStatus, tt.args.w.Code)
+
+			if tt.wantBody == nil {
+				return
+			}
+
+			var body *models.ImportReportV1
+			decoded := models.ImportReportV1{}
+			err := json.NewDecoder(tt.args.w.Body).Decode(&decoded)
+
+			if err != nil && err != io.EOF {
+				t.Fatal(err)
+			}
+
+			if err == nil {
+				body = &decoded
+			}
+
+			require.Equal(t, body, tt.wantBody)
+		})
+	}
+}
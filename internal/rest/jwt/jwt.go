@@ -0,0 +1,206 @@
+// Package jwt validates signed JSON Web Tokens (RFC 7519) with keys of local JWKS.
+// Only HS256 and RS256 algorithms are supported.
+package jwt
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+const (
+	algorithmHS256 = "HS256"
+	algorithmRS256 = "RS256"
+)
+
+// Allowed clock skew between token issuer and the service.
+const leeway = time.Minute
+
+var (
+	ErrMalformed        = errors.New("token is malformed")
+	ErrUnsupported      = errors.New("token signing algorithm is not supported")
+	ErrInvalidSignature = errors.New("token signature is invalid")
+	ErrExpired          = errors.New("token is expired or not valid yet")
+	ErrInvalidClaims    = errors.New("token issuer or audience is not accepted")
+)
+
+type Validator struct {
+	Keys     *KeySet
+	Issuer   string // not checked if empty
+	Audience string // not checked if empty
+}
+
+// Registered claims with access to all other claims of the token.
+type Claims struct {
+	Subject   string
+	Issuer    string
+	Audience  []string
+	ExpiresAt time.Time
+	NotBefore time.Time
+	all       map[string]any
+}
+
+// Returns claims of the token if it is signed by one of the keys and valid at the moment.
+// Expiration time is required.
+func (v *Validator) Validate(token string, now time.Time) (*Claims, error) {
+	parts := strings.Split(token, ".")
+
+	if len(parts) != 3 {
+		return nil, ErrMalformed
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+
+	if decodeSegment(parts[0], &header) != nil {
+		return nil, ErrMalformed
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+
+	if err != nil {
+		return nil, ErrMalformed
+	}
+
+	if header.Alg != algorithmHS256 && header.Alg != algorithmRS256 {
+		return nil, ErrUnsupported
+	}
+
+	if !v.verified(header.Alg, header.Kid, parts[0]+"."+parts[1], signature) {
+		return nil, ErrInvalidSignature
+	}
+
+	claims := &Claims{}
+
+	if decodeSegment(parts[1], &claims.all) != nil || claims.parse() != nil {
+		return nil, ErrMalformed
+	}
+
+	if claims.ExpiresAt.IsZero() || !now.Before(claims.ExpiresAt.Add(leeway)) || now.Add(leeway).Before(claims.NotBefore) {
+		return nil, ErrExpired
+	}
+
+	if v.Issuer != "" && claims.Issuer != v.Issuer || v.Audience != "" && !contains(claims.Audience, v.Audience) {
+		return nil, ErrInvalidClaims
+	}
+
+	return claims, nil
+}
+
+func (v *Validator) verified(algorithm, keyId, signed string, signature []byte) bool {
+	if v.Keys == nil {
+		return false
+	}
+
+	digest := sha256.Sum256([]byte(signed))
+
+	for _, k := range v.Keys.candidates(algorithm, keyId) {
+		switch algorithm {
+		case algorithmHS256:
+			mac := hmac.New(sha256.New, k.secret)
+			mac.Write([]byte(signed))
+
+			if hmac.Equal(signature, mac.Sum(nil)) {
+				return true
+			}
+		case algorithmRS256:
+			if rsa.VerifyPKCS1v15(k.public, crypto.SHA256, digest[:], signature) == nil {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Claim value as a list of strings (single string is a list of one element).
+// Nested claims are addressed by dotted path, e.g. "realm_access.roles".
+func (c *Claims) Strings(name string) (values []string) {
+	var value any = c.all
+
+	for _, part := range strings.Split(name, ".") {
+		object, ok := value.(map[string]any)
+
+		if !ok {
+			return nil
+		}
+
+		value = object[part]
+	}
+
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []any:
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+	}
+
+	return values
+}
+
+func (c *Claims) parse() (err error) {
+	c.Subject, _ = c.all["sub"].(string)
+	c.Issuer, _ = c.all["iss"].(string)
+	c.Audience = c.Strings("aud")
+
+	c.ExpiresAt, err = numericDate(c.all["exp"])
+
+	if err == nil {
+		c.NotBefore, err = numericDate(c.all["nbf"])
+	}
+
+	return err
+}
+
+// Seconds since the epoch, zero time if claim is absent.
+func numericDate(value any) (time.Time, error) {
+	switch v := value.(type) {
+	case nil:
+		return time.Time{}, nil
+	case json.Number:
+		seconds, err := v.Float64()
+
+		if err != nil {
+			return time.Time{}, err
+		}
+
+		return time.Unix(0, int64(seconds*float64(time.Second))), nil
+	}
+
+	return time.Time{}, ErrMalformed
+}
+
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+
+	if err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(string(data)))
+	decoder.UseNumber()
+
+	return decoder.Decode(v)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
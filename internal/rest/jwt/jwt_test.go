@@ -0,0 +1,164 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testSecret = []byte("test-secret-of-sufficient-length")
+	testNow    = time.Date(2023, 10, 1, 12, 0, 0, 0, time.UTC)
+)
+
+func TestValidator_Validate(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	keys, err := ParseKeySet([]byte(fmt.Sprintf(`{"keys": [
+		{"kty": "oct", "kid": "hmac", "alg": "HS256", "k": "%s"},
+		{"kty": "RSA", "kid": "rsa", "use": "sig", "n": "%s", "e": "%s"},
+		{"kty": "EC", "kid": "ec", "crv": "P-256"}
+	]}`,
+		base64.RawURLEncoding.EncodeToString(testSecret),
+		base64.RawURLEncoding.EncodeToString(rsaKey.N.Bytes()),
+		base64.RawURLEncoding.EncodeToString(big.NewInt(int64(rsaKey.E)).Bytes()))))
+	require.NoError(t, err)
+
+	v := &Validator{Keys: keys, Issuer: "https://sso.example.com", Audience: "demography"}
+
+	valid := map[string]any{
+		"sub": "jdoe",
+		"iss": "https://sso.example.com",
+		"aud": "demography",
+		"exp": testNow.Add(time.Hour).Unix(),
+	}
+
+	with := func(name string, value any) map[string]any {
+		claims := make(map[string]any, len(valid))
+		for k, v := range valid {
+			claims[k] = v
+		}
+		if value == nil {
+			delete(claims, name)
+		} else {
+			claims[name] = value
+		}
+		return claims
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr error
+	}{
+		{name: "HS256", token: signHS256(t, "hmac", testSecret, valid)},
+		{name: "RS256", token: signRS256(t, "rsa", rsaKey, valid)},
+		{name: "Key without id", token: signHS256(t, "", testSecret, valid)},
+		{name: "Audience list", token: signHS256(t, "hmac", testSecret, with("aud", []string{"other", "demography"}))},
+		{name: "Within leeway", token: signHS256(t, "hmac", testSecret, with("exp", testNow.Add(-30*time.Second).Unix()))},
+		{name: "Malformed", token: "not.a-token", wantErr: ErrMalformed},
+		{name: "Unsigned", token: sign(t, "none", "", valid, func(string) []byte { return nil }), wantErr: ErrUnsupported},
+		{name: "Wrong secret", token: signHS256(t, "hmac", []byte("other"), valid), wantErr: ErrInvalidSignature},
+		{name: "Unknown key", token: signHS256(t, "unknown", testSecret, valid), wantErr: ErrInvalidSignature},
+		{name: "Algorithm confusion", token: signHS256(t, "rsa", rsaKey.N.Bytes(), valid), wantErr: ErrInvalidSignature},
+		{name: "Expired", token: signHS256(t, "hmac", testSecret, with("exp", testNow.Add(-time.Hour).Unix())), wantErr: ErrExpired},
+		{name: "No expiration", token: signHS256(t, "hmac", testSecret, with("exp", nil)), wantErr: ErrExpired},
+		{name: "Not valid yet", token: signHS256(t, "hmac", testSecret, with("nbf", testNow.Add(time.Hour).Unix())), wantErr: ErrExpired},
+		{name: "Wrong issuer", token: signHS256(t, "hmac", testSecret, with("iss", "https://other.example.com")), wantErr: ErrInvalidClaims},
+		{name: "Wrong audience", token: signHS256(t, "hmac", testSecret, with("aud", "other")), wantErr: ErrInvalidClaims},
+		{name: "Incorrect expiration", token: signHS256(t, "hmac", testSecret, with("exp", "tomorrow")), wantErr: ErrMalformed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := v.Validate(tt.token, testNow)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				require.Nil(t, claims)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, "jdoe", claims.Subject)
+		})
+	}
+
+	_, err = (&Validator{}).Validate(signHS256(t, "hmac", testSecret, valid), testNow)
+	require.ErrorIs(t, err, ErrInvalidSignature)
+}
+
+func TestClaims_Strings(t *testing.T) {
+	v := &Validator{Keys: &KeySet{keys: []*key{{algorithm: algorithmHS256, secret: testSecret}}}}
+
+	claims, err := v.Validate(signHS256(t, "", testSecret, map[string]any{
+		"exp":          testNow.Add(time.Hour).Unix(),
+		"role":         "viewer",
+		"roles":        []any{"editor", 1, "admin"},
+		"realm_access": map[string]any{"roles": []string{"admin"}},
+	}), testNow)
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"viewer"}, claims.Strings("role"))
+	require.Equal(t, []string{"editor", "admin"}, claims.Strings("roles"))
+	require.Equal(t, []string{"admin"}, claims.Strings("realm_access.roles"))
+	require.Empty(t, claims.Strings("realm_access.groups"))
+	require.Empty(t, claims.Strings("role.name"))
+}
+
+func TestParseKeySet(t *testing.T) {
+	_, err := ParseKeySet([]byte(`{"keys": [{"kty": "oct", "use": "enc", "k": "c2VjcmV0"}]}`))
+	require.Error(t, err)
+
+	_, err = ParseKeySet([]byte(`{"keys": [{"kty": "oct", "k": "!"}]}`))
+	require.Error(t, err)
+
+	_, err = ParseKeySet([]byte(`not json`))
+	require.Error(t, err)
+}
+
+func signHS256(t *testing.T, keyId string, secret []byte, claims map[string]any) string {
+	return sign(t, algorithmHS256, keyId, claims, func(signed string) []byte {
+		mac := hmac.New(sha256.New, secret)
+		mac.Write([]byte(signed))
+		return mac.Sum(nil)
+	})
+}
+
+func signRS256(t *testing.T, keyId string, private *rsa.PrivateKey, claims map[string]any) string {
+	return sign(t, algorithmRS256, keyId, claims, func(signed string) []byte {
+		digest := sha256.Sum256([]byte(signed))
+		signature, err := rsa.SignPKCS1v15(rand.Reader, private, crypto.SHA256, digest[:])
+		require.NoError(t, err)
+		return signature
+	})
+}
+
+func sign(t *testing.T, algorithm, keyId string, claims map[string]any, signature func(signed string) []byte) string {
+	header := map[string]any{"alg": algorithm, "typ": "JWT"}
+
+	if keyId != "" {
+		header["kid"] = keyId
+	}
+
+	encode := func(v any) string {
+		data, err := json.Marshal(v)
+		require.NoError(t, err)
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+
+	signed := encode(header) + "." + encode(claims)
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature(signed))
+}
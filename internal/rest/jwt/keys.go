@@ -0,0 +1,119 @@
+package jwt
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+)
+
+// Verification keys of JSON Web Key Set (RFC 7517). Only symmetric (oct) keys for HS256
+// and RSA public keys for RS256 are supported, other keys are skipped.
+type KeySet struct {
+	keys []*key
+}
+
+type key struct {
+	id        string
+	algorithm string // HS256 or RS256
+	secret    []byte
+	public    *rsa.PublicKey
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	K   string `json:"k"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+func ReadKeySet(path string) (*KeySet, error) {
+	data, err := os.ReadFile(path)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JWKS file: %w", err)
+	}
+
+	return ParseKeySet(data)
+}
+
+func ParseKeySet(data []byte) (*KeySet, error) {
+	var jwks struct {
+		Keys []*jsonWebKey `json:"keys"`
+	}
+
+	err := json.Unmarshal(data, &jwks)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse JWKS: %w", err)
+	}
+
+	set := &KeySet{}
+
+	for i, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		var k *key
+		k, err = parseKey(jwk)
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse JWKS key #%d: %w", i, err)
+		}
+
+		if k != nil {
+			set.keys = append(set.keys, k)
+		}
+	}
+
+	if len(set.keys) == 0 {
+		return nil, errors.New("JWKS contains no supported signature keys")
+	}
+
+	return set, nil
+}
+
+// Returns nil if key type or algorithm is not supported.
+func parseKey(jwk *jsonWebKey) (*key, error) {
+	switch {
+	case jwk.Kty == "oct" && (jwk.Alg == "" || jwk.Alg == algorithmHS256):
+		secret, err := base64.RawURLEncoding.DecodeString(jwk.K)
+
+		if err != nil || len(secret) == 0 {
+			return nil, errors.New("incorrect symmetric key value")
+		}
+
+		return &key{id: jwk.Kid, algorithm: algorithmHS256, secret: secret}, nil
+	case jwk.Kty == "RSA" && (jwk.Alg == "" || jwk.Alg == algorithmRS256):
+		n, errN := base64.RawURLEncoding.DecodeString(jwk.N)
+		e, errE := base64.RawURLEncoding.DecodeString(jwk.E)
+
+		if errN != nil || errE != nil || len(n) == 0 || len(e) == 0 || len(e) > 4 {
+			return nil, errors.New("incorrect RSA public key value")
+		}
+
+		public := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+
+		return &key{id: jwk.Kid, algorithm: algorithmRS256, public: public}, nil
+	}
+
+	return nil, nil
+}
+
+// Keys suitable for verification of the token signature.
+func (s *KeySet) candidates(algorithm, keyId string) (keys []*key) {
+	for _, k := range s.keys {
+		if k.algorithm == algorithm && (keyId == "" || k.id == keyId) {
+			keys = append(keys, k)
+		}
+	}
+
+	return keys
+}
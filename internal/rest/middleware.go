@@ -1,32 +1,57 @@
 package rest
 
 import (
+	"context"
 	"fmt"
+	"net"
 	"net/http"
 
+	"github.com/barpav/demography/internal/actor"
+	"github.com/go-chi/chi/v5/middleware"
 	"github.com/rs/zerolog/log"
 )
 
-// Normally done by front end web server.
-func (s *Service) enableCORS(next http.Handler) http.Handler {
+type loggedClientKey struct{}
+
+// Requests (including CORS preflights and rejected ones) are logged on completion on behalf of the client,
+// which is known after authentication (see Service.authenticate).
+func (s *Service) logRequest(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		log.Info().Msg(fmt.Sprintf("%s %s", r.Method, r.RequestURI))
-
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "POST, GET, HEAD, PUT, PATCH, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers",
-			"Accept,DNT,User-Agent,X-Requested-With,If-Modified-Since,Cache-Control,Content-Type,Range")
-
-		if r.Method == http.MethodOptions {
-			w.Header().Set("Access-Control-Max-Age", "1728000") // valid for 20 days
-			w.WriteHeader(http.StatusNoContent)
-			log.Debug().Msg("CORS options returned")
-			return
+		client := clientAddress(r)
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+		next.ServeHTTP(ww, r.WithContext(context.WithValue(r.Context(), loggedClientKey{}, &client)))
+
+		status := ww.Status()
+
+		if status == 0 {
+			status = http.StatusOK
 		}
 
-		w.Header().Set("Access-Control-Expose-Headers",
-			"Content-Type,Content-Length,Content-Range,Content-Disposition")
+		log.Info().Msg(fmt.Sprintf("%s %s (%s): %d", r.Method, r.RequestURI, client, status))
+	})
+}
 
-		next.ServeHTTP(w, r)
+func logAuthenticatedClient(r *http.Request, name string) {
+	if client, ok := r.Context().Value(loggedClientKey{}).(*string); ok && name != "" {
+		*client = name
+	}
+}
+
+// Changes of people data are recorded in history on behalf of the client.
+// Unless authenticated, clients are identified by network address.
+func (s *Service) identifyActor(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(actor.NewContext(r.Context(), clientAddress(r))))
 	})
 }
+
+func clientAddress(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	return "address:" + host
+}
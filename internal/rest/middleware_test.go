@@ -0,0 +1,63 @@
+package rest
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/barpav/demography/internal/actor"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_identifyActor(t *testing.T) {
+	var identified string
+	handler := (&Service{}).identifyActor(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		identified = actor.FromContext(r.Context())
+	}))
+
+	r := httptest.NewRequest("PATCH", "/v1/people/101", nil)
+	r.RemoteAddr = "192.0.2.1:41234"
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+
+	require.Equal(t, "address:192.0.2.1", identified)
+}
+
+func TestService_logRequest(t *testing.T) {
+	var logged bytes.Buffer
+	defer func(logger zerolog.Logger) { log.Logger = logger }(log.Logger)
+	log.Logger = zerolog.New(&logged)
+
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		wantLog string
+	}{
+		{
+			name:    "Client is not authenticated",
+			handler: func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusUnauthorized) },
+			wantLog: "PATCH /v1/people/101 (address:192.0.2.1): 401",
+		},
+		{
+			name: "Client is authenticated",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				logAuthenticatedClient(r, "api-key:reporting")
+			},
+			wantLog: "PATCH /v1/people/101 (api-key:reporting): 200",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logged.Reset()
+
+			r := httptest.NewRequest("PATCH", "/v1/people/101", nil)
+			r.RemoteAddr = "192.0.2.1:41234"
+			(&Service{}).logRequest(tt.handler).ServeHTTP(httptest.NewRecorder(), r)
+
+			require.Contains(t, logged.String(), tt.wantLog)
+		})
+	}
+}
@@ -4,6 +4,7 @@ package mocks
 
 import (
 	context "context"
+	time "time"
 
 	models "github.com/barpav/demography/internal/rest/models"
 	mock "github.com/stretchr/testify/mock"
@@ -14,6 +15,96 @@ type Storage struct {
 	mock.Mock
 }
 
+// APIKey provides a mock function with given fields: ctx, keyHash
+func (_m *Storage) APIKey(ctx context.Context, keyHash []byte) (*models.APIKey, error) {
+	ret := _m.Called(ctx, keyHash)
+
+	var r0 *models.APIKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []byte) (*models.APIKey, error)); ok {
+		return rf(ctx, keyHash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []byte) *models.APIKey); ok {
+		r0 = rf(ctx, keyHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.APIKey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []byte) error); ok {
+		r1 = rf(ctx, keyHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AgeHistogramV1 provides a mock function with given fields: ctx, filters, width
+func (_m *Storage) AgeHistogramV1(ctx context.Context, filters *models.SearchFilters, width int) (*models.AgeHistogramV1, error) {
+	ret := _m.Called(ctx, filters, width)
+
+	var r0 *models.AgeHistogramV1
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.SearchFilters, int) (*models.AgeHistogramV1, error)); ok {
+		return rf(ctx, filters, width)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.SearchFilters, int) *models.AgeHistogramV1); ok {
+		r0 = rf(ctx, filters, width)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.AgeHistogramV1)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.SearchFilters, int) error); ok {
+		r1 = rf(ctx, filters, width)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CountPeople provides a mock function with given fields: ctx, filters
+func (_m *Storage) CountPeople(ctx context.Context, filters *models.SearchFilters) (int64, error) {
+	ret := _m.Called(ctx, filters)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.SearchFilters) (int64, error)); ok {
+		return rf(ctx, filters)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.SearchFilters) int64); ok {
+		r0 = rf(ctx, filters)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.SearchFilters) error); ok {
+		r1 = rf(ctx, filters)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateNewPeopleDataV1 provides a mock function with given fields: ctx, data
+func (_m *Storage) CreateNewPeopleDataV1(ctx context.Context, data []*models.EnrichedPersonDataV1) error {
+	ret := _m.Called(ctx, data)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []*models.EnrichedPersonDataV1) error); ok {
+		r0 = rf(ctx, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CreateNewPersonDataV1 provides a mock function with given fields: ctx, data
 func (_m *Storage) CreateNewPersonDataV1(ctx context.Context, data *models.EnrichedPersonDataV1) error {
 	ret := _m.Called(ctx, data)
@@ -28,13 +119,51 @@ func (_m *Storage) CreateNewPersonDataV1(ctx context.Context, data *models.Enric
 	return r0
 }
 
-// DeletePersonData provides a mock function with given fields: ctx, id
-func (_m *Storage) DeletePersonData(ctx context.Context, id int64) error {
-	ret := _m.Called(ctx, id)
+// CreateNewPersonDataV2 provides a mock function with given fields: ctx, data
+func (_m *Storage) CreateNewPersonDataV2(ctx context.Context, data *models.EnrichedPersonDataV2) error {
+	ret := _m.Called(ctx, data)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
-		r0 = rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, *models.EnrichedPersonDataV2) error); ok {
+		r0 = rf(ctx, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeletePeople provides a mock function with given fields: ctx, filters, expected
+func (_m *Storage) DeletePeople(ctx context.Context, filters *models.SearchFilters, expected int64) (int64, error) {
+	ret := _m.Called(ctx, filters, expected)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.SearchFilters, int64) (int64, error)); ok {
+		return rf(ctx, filters, expected)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.SearchFilters, int64) int64); ok {
+		r0 = rf(ctx, filters, expected)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.SearchFilters, int64) error); ok {
+		r1 = rf(ctx, filters, expected)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeletePersonData provides a mock function with given fields: ctx, id, versions
+func (_m *Storage) DeletePersonData(ctx context.Context, id int64, versions []int) error {
+	ret := _m.Called(ctx, id, versions)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, []int) error); ok {
+		r0 = rf(ctx, id, versions)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -42,6 +171,58 @@ func (_m *Storage) DeletePersonData(ctx context.Context, id int64) error {
 	return r0
 }
 
+// DeletedPeopleV1 provides a mock function with given fields: ctx, after, limit
+func (_m *Storage) DeletedPeopleV1(ctx context.Context, after int64, limit int) (*models.DeletedPeopleV1, error) {
+	ret := _m.Called(ctx, after, limit)
+
+	var r0 *models.DeletedPeopleV1
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int) (*models.DeletedPeopleV1, error)); ok {
+		return rf(ctx, after, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int) *models.DeletedPeopleV1); ok {
+		r0 = rf(ctx, after, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.DeletedPeopleV1)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int) error); ok {
+		r1 = rf(ctx, after, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DemographicsV1 provides a mock function with given fields: ctx, filters, grouping
+func (_m *Storage) DemographicsV1(ctx context.Context, filters *models.SearchFilters, grouping *models.DemographicsGrouping) (*models.DemographicsV1, error) {
+	ret := _m.Called(ctx, filters, grouping)
+
+	var r0 *models.DemographicsV1
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.SearchFilters, *models.DemographicsGrouping) (*models.DemographicsV1, error)); ok {
+		return rf(ctx, filters, grouping)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.SearchFilters, *models.DemographicsGrouping) *models.DemographicsV1); ok {
+		r0 = rf(ctx, filters, grouping)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.DemographicsV1)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.SearchFilters, *models.DemographicsGrouping) error); ok {
+		r1 = rf(ctx, filters, grouping)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // EnrichedPersonDataV1 provides a mock function with given fields: ctx, id
 func (_m *Storage) EnrichedPersonDataV1(ctx context.Context, id int64) (*models.EnrichedPersonDataV1, error) {
 	ret := _m.Called(ctx, id)
@@ -68,6 +249,364 @@ func (_m *Storage) EnrichedPersonDataV1(ctx context.Context, id int64) (*models.
 	return r0, r1
 }
 
+// EnrichedPersonDataV1AsOf provides a mock function with given fields: ctx, id, asOf
+func (_m *Storage) EnrichedPersonDataV1AsOf(ctx context.Context, id int64, asOf time.Time) (*models.EnrichedPersonDataV1, error) {
+	ret := _m.Called(ctx, id, asOf)
+
+	var r0 *models.EnrichedPersonDataV1
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time) (*models.EnrichedPersonDataV1, error)); ok {
+		return rf(ctx, id, asOf)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time) *models.EnrichedPersonDataV1); ok {
+		r0 = rf(ctx, id, asOf)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.EnrichedPersonDataV1)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, time.Time) error); ok {
+		r1 = rf(ctx, id, asOf)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// EnrichedPersonDataV2 provides a mock function with given fields: ctx, id
+func (_m *Storage) EnrichedPersonDataV2(ctx context.Context, id int64) (*models.EnrichedPersonDataV2, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *models.EnrichedPersonDataV2
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*models.EnrichedPersonDataV2, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *models.EnrichedPersonDataV2); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.EnrichedPersonDataV2)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// EnrichedPersonDataV2AsOf provides a mock function with given fields: ctx, id, asOf
+func (_m *Storage) EnrichedPersonDataV2AsOf(ctx context.Context, id int64, asOf time.Time) (*models.EnrichedPersonDataV2, error) {
+	ret := _m.Called(ctx, id, asOf)
+
+	var r0 *models.EnrichedPersonDataV2
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time) (*models.EnrichedPersonDataV2, error)); ok {
+		return rf(ctx, id, asOf)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time) *models.EnrichedPersonDataV2); ok {
+		r0 = rf(ctx, id, asOf)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.EnrichedPersonDataV2)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, time.Time) error); ok {
+		r1 = rf(ctx, id, asOf)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ExportSearchResultV1 provides a mock function with given fields: ctx, filters, receive
+func (_m *Storage) ExportSearchResultV1(ctx context.Context, filters *models.SearchFilters, receive func(*models.EnrichedPersonDataV1) error) error {
+	ret := _m.Called(ctx, filters, receive)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.SearchFilters, func(*models.EnrichedPersonDataV1) error) error); ok {
+		r0 = rf(ctx, filters, receive)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// HistoryStart provides a mock function with given fields: ctx
+func (_m *Storage) HistoryStart(ctx context.Context) (time.Time, error) {
+	ret := _m.Called(ctx)
+
+	var r0 time.Time
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (time.Time, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) time.Time); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(time.Time)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ImportPeopleDataV1 provides a mock function with given fields: ctx, data
+func (_m *Storage) ImportPeopleDataV1(ctx context.Context, data []*models.EnrichedPersonDataV1) error {
+	ret := _m.Called(ctx, data)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []*models.EnrichedPersonDataV1) error); ok {
+		r0 = rf(ctx, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NamePopularityV1 provides a mock function with given fields: ctx, field, filters, limit
+func (_m *Storage) NamePopularityV1(ctx context.Context, field models.NameField, filters *models.SearchFilters, limit int) (*models.NamePopularityV1, error) {
+	ret := _m.Called(ctx, field, filters, limit)
+
+	var r0 *models.NamePopularityV1
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.NameField, *models.SearchFilters, int) (*models.NamePopularityV1, error)); ok {
+		return rf(ctx, field, filters, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.NameField, *models.SearchFilters, int) *models.NamePopularityV1); ok {
+		r0 = rf(ctx, field, filters, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.NamePopularityV1)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.NameField, *models.SearchFilters, int) error); ok {
+		r1 = rf(ctx, field, filters, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NameSummaryV1 provides a mock function with given fields: ctx, filters
+func (_m *Storage) NameSummaryV1(ctx context.Context, filters *models.SearchFilters) (*models.NameSummaryV1, error) {
+	ret := _m.Called(ctx, filters)
+
+	var r0 *models.NameSummaryV1
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.SearchFilters) (*models.NameSummaryV1, error)); ok {
+		return rf(ctx, filters)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.SearchFilters) *models.NameSummaryV1); ok {
+		r0 = rf(ctx, filters)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.NameSummaryV1)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.SearchFilters) error); ok {
+		r1 = rf(ctx, filters)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PatchPeopleDataV1 provides a mock function with given fields: ctx, filters, patch, expected
+func (_m *Storage) PatchPeopleDataV1(ctx context.Context, filters *models.SearchFilters, patch *models.PersonDataPatchV1, expected int64) (int64, error) {
+	ret := _m.Called(ctx, filters, patch, expected)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.SearchFilters, *models.PersonDataPatchV1, int64) (int64, error)); ok {
+		return rf(ctx, filters, patch, expected)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.SearchFilters, *models.PersonDataPatchV1, int64) int64); ok {
+		r0 = rf(ctx, filters, patch, expected)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.SearchFilters, *models.PersonDataPatchV1, int64) error); ok {
+		r1 = rf(ctx, filters, patch, expected)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PatchPersonDataV1 provides a mock function with given fields: ctx, id, patch, versions
+func (_m *Storage) PatchPersonDataV1(ctx context.Context, id int64, patch *models.PersonDataPatchV1, versions []int) (int, error) {
+	ret := _m.Called(ctx, id, patch, versions)
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, *models.PersonDataPatchV1, []int) (int, error)); ok {
+		return rf(ctx, id, patch, versions)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, *models.PersonDataPatchV1, []int) int); ok {
+		r0 = rf(ctx, id, patch, versions)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, *models.PersonDataPatchV1, []int) error); ok {
+		r1 = rf(ctx, id, patch, versions)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PersonDataExists provides a mock function with given fields: ctx, id
+func (_m *Storage) PersonDataExists(ctx context.Context, id int64) (bool, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (bool, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) bool); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PersonDataHistoryV1 provides a mock function with given fields: ctx, id, after, limit
+func (_m *Storage) PersonDataHistoryV1(ctx context.Context, id int64, after int64, limit int) (*models.PersonDataHistoryV1, error) {
+	ret := _m.Called(ctx, id, after, limit)
+
+	var r0 *models.PersonDataHistoryV1
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, int) (*models.PersonDataHistoryV1, error)); ok {
+		return rf(ctx, id, after, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, int) *models.PersonDataHistoryV1); ok {
+		r0 = rf(ctx, id, after, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.PersonDataHistoryV1)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64, int) error); ok {
+		r1 = rf(ctx, id, after, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReleaseIdempotencyKey provides a mock function with given fields: ctx, client, key, reservation
+func (_m *Storage) ReleaseIdempotencyKey(ctx context.Context, client string, key string, reservation []byte) error {
+	ret := _m.Called(ctx, client, key, reservation)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, []byte) error); ok {
+		r0 = rf(ctx, client, key, reservation)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReserveIdempotencyKey provides a mock function with given fields: ctx, client, key, reservation, fingerprint, lock, ttl
+func (_m *Storage) ReserveIdempotencyKey(ctx context.Context, client string, key string, reservation []byte, fingerprint []byte, lock time.Duration, ttl time.Duration) (*models.IdempotentResponse, error) {
+	ret := _m.Called(ctx, client, key, reservation, fingerprint, lock, ttl)
+
+	var r0 *models.IdempotentResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, []byte, []byte, time.Duration, time.Duration) (*models.IdempotentResponse, error)); ok {
+		return rf(ctx, client, key, reservation, fingerprint, lock, ttl)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, []byte, []byte, time.Duration, time.Duration) *models.IdempotentResponse); ok {
+		r0 = rf(ctx, client, key, reservation, fingerprint, lock, ttl)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.IdempotentResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, []byte, []byte, time.Duration, time.Duration) error); ok {
+		r1 = rf(ctx, client, key, reservation, fingerprint, lock, ttl)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RestorePersonData provides a mock function with given fields: ctx, id
+func (_m *Storage) RestorePersonData(ctx context.Context, id int64) (int, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (int, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) int); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SaveIdempotentResponse provides a mock function with given fields: ctx, client, key, reservation, response
+func (_m *Storage) SaveIdempotentResponse(ctx context.Context, client string, key string, reservation []byte, response *models.IdempotentResponse) error {
+	ret := _m.Called(ctx, client, key, reservation, response)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, []byte, *models.IdempotentResponse) error); ok {
+		r0 = rf(ctx, client, key, reservation, response)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // SearchResultV1 provides a mock function with given fields: ctx, filters
 func (_m *Storage) SearchResultV1(ctx context.Context, filters *models.SearchFilters) (*models.SearchResultV1, error) {
 	ret := _m.Called(ctx, filters)
@@ -94,18 +633,78 @@ func (_m *Storage) SearchResultV1(ctx context.Context, filters *models.SearchFil
 	return r0, r1
 }
 
-// UpdatePersonDataV1 provides a mock function with given fields: ctx, id, data
-func (_m *Storage) UpdatePersonDataV1(ctx context.Context, id int64, data *models.EditedPersonDataV1) error {
-	ret := _m.Called(ctx, id, data)
+// SearchResultV2 provides a mock function with given fields: ctx, filters
+func (_m *Storage) SearchResultV2(ctx context.Context, filters *models.SearchFilters) (*models.SearchResultV2, error) {
+	ret := _m.Called(ctx, filters)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, int64, *models.EditedPersonDataV1) error); ok {
-		r0 = rf(ctx, id, data)
+	var r0 *models.SearchResultV2
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.SearchFilters) (*models.SearchResultV2, error)); ok {
+		return rf(ctx, filters)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.SearchFilters) *models.SearchResultV2); ok {
+		r0 = rf(ctx, filters)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.SearchResultV2)
+		}
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(context.Context, *models.SearchFilters) error); ok {
+		r1 = rf(ctx, filters)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdatePersonDataV1 provides a mock function with given fields: ctx, id, data, versions
+func (_m *Storage) UpdatePersonDataV1(ctx context.Context, id int64, data *models.EditedPersonDataV1, versions []int) (int, error) {
+	ret := _m.Called(ctx, id, data, versions)
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, *models.EditedPersonDataV1, []int) (int, error)); ok {
+		return rf(ctx, id, data, versions)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, *models.EditedPersonDataV1, []int) int); ok {
+		r0 = rf(ctx, id, data, versions)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, *models.EditedPersonDataV1, []int) error); ok {
+		r1 = rf(ctx, id, data, versions)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdatePersonDataV2 provides a mock function with given fields: ctx, id, data, versions
+func (_m *Storage) UpdatePersonDataV2(ctx context.Context, id int64, data *models.EditedPersonDataV2, versions []int) (int, error) {
+	ret := _m.Called(ctx, id, data, versions)
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, *models.EditedPersonDataV2, []int) (int, error)); ok {
+		return rf(ctx, id, data, versions)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, *models.EditedPersonDataV2, []int) int); ok {
+		r0 = rf(ctx, id, data, versions)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, *models.EditedPersonDataV2, []int) error); ok {
+		r1 = rf(ctx, id, data, versions)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // NewStorage creates a new instance of Storage. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
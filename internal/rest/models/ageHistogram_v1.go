@@ -0,0 +1,51 @@
+package models
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+)
+
+const MimeTypeAgeHistogramV1 = "application/vnd.ageHistogram.v1+json"
+
+// Schema: ageHistogram.v1
+type AgeHistogramV1 struct {
+	BucketWidth int            `json:"bucketWidth"`
+	Total       int64          `json:"total"`
+	Buckets     []*AgeBucketV1 `json:"buckets,omitempty"`
+}
+
+type AgeBucketV1 struct {
+	From    int   `json:"from"`
+	To      int   `json:"to"` // inclusive
+	Male    int64 `json:"male"`
+	Female  int64 `json:"female"`
+	Unknown int64 `json:"unknown"` // gender is not determined or not supported by version 1
+}
+
+func (m *AgeHistogramV1) SerializeCSV(w io.Writer) error {
+	records := csv.NewWriter(w)
+	err := records.Write([]string{"from", "to", "male", "female", "unknown"})
+
+	for _, b := range m.Buckets {
+		if err != nil {
+			break
+		}
+
+		err = records.Write([]string{
+			strconv.Itoa(b.From),
+			strconv.Itoa(b.To),
+			strconv.FormatInt(b.Male, 10),
+			strconv.FormatInt(b.Female, 10),
+			strconv.FormatInt(b.Unknown, 10),
+		})
+	}
+
+	if err != nil {
+		return err
+	}
+
+	records.Flush()
+
+	return records.Error()
+}
@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// Credentials of API client, key itself is not stored.
+type APIKey struct {
+	Name      string
+	Scopes    []string
+	CreatedAt time.Time
+	RevokedAt time.Time // zero if key is active
+}
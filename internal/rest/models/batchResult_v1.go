@@ -0,0 +1,14 @@
+package models
+
+const MimeTypeBatchResultV1 = "application/vnd.batchResult.v1+json"
+
+// Schema: batchResult.v1
+type BatchResultV1 struct {
+	Items []*BatchItemResultV1 `json:"items"` // in the same order as in the batch
+}
+
+type BatchItemResultV1 struct {
+	Status int                   `json:"status"` // HTTP status code of the item operation
+	Data   *EnrichedPersonDataV1 `json:"data,omitempty"`
+	Errors []string              `json:"errors,omitempty"`
+}
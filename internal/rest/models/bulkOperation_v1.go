@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+const MimeTypeBulkOperationV1 = "application/vnd.bulkOperation.v1+json"
+
+// Schema: bulkOperation.v1
+type BulkOperationV1 struct {
+	DryRun            bool       `json:"dryRun"`
+	Affected          int64      `json:"affected"`                    // people matching filters (dry run) or actually changed
+	ConfirmationToken string     `json:"confirmationToken,omitempty"` // dry run only
+	ExpiresAt         *time.Time `json:"expiresAt,omitempty"`         // of confirmation token
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"strings"
+
+	"github.com/barpav/demography/internal/countries"
+)
+
+// Schema: country.v1 (expanded representation of ISO 3166-1 alpha-2 country code)
+type CountryV1 struct {
+	Code   string         `json:"code"`
+	Names  CountryNamesV1 `json:"names"`
+	Region string         `json:"region"`
+}
+
+type CountryNamesV1 struct {
+	En string `json:"en"`
+	Ru string `json:"ru"`
+}
+
+// Returns nil if country code is unknown.
+func NewCountryV1(code string) *CountryV1 {
+	country, ok := countries.Lookup(code)
+
+	if !ok {
+		return nil
+	}
+
+	return &CountryV1{
+		Code:   country.Code,
+		Names:  CountryNamesV1{En: country.NameEn, Ru: country.NameRu},
+		Region: country.Region,
+	}
+}
+
+// Known codes are converted to canonical case, unknown ones are kept for validation.
+func normalizedCountry(code string) string {
+	code = strings.TrimSpace(code)
+
+	if normalized, ok := countries.Normalize(code); ok {
+		return normalized
+	}
+
+	return code
+}
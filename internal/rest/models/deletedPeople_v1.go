@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+const MimeTypeDeletedPeopleV1 = "application/vnd.deletedPeople.v1+json"
+
+// Schema: deletedPeople.v1
+type DeletedPeopleV1 struct {
+	Total int                    `json:"total"`
+	Data  []*DeletedPersonDataV1 `json:"data,omitempty"`
+}
+
+// Schema: deletedPersonData.v1
+type DeletedPersonDataV1 struct {
+	EnrichedPersonDataV1
+	DeletedAt time.Time `json:"deletedAt"`
+	PurgeAt   time.Time `json:"purgeAt"` // person data cannot be restored after that
+}
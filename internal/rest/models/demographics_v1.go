@@ -0,0 +1,34 @@
+package models
+
+const MimeTypeDemographicsV1 = "application/vnd.demographics.v1+json"
+
+// Schema: demographics.v1
+type DemographicsV1 struct {
+	Total  int64                  `json:"total"`
+	Groups []*DemographicsGroupV1 `json:"groups,omitempty"`
+}
+
+type DemographicsGroupV1 struct {
+	Gender  string           `json:"gender,omitempty"`
+	Country string           `json:"country,omitempty"`
+	Count   int64            `json:"count"`
+	Age     *AgeStatisticsV1 `json:"age,omitempty"` // absent if age is unknown for the whole group
+}
+
+// Calculated only for people with known age.
+type AgeStatisticsV1 struct {
+	Count  int64   `json:"count"`
+	Min    int     `json:"min"`
+	Max    int     `json:"max"`
+	Mean   float64 `json:"mean"`
+	Median float64 `json:"median"`
+	P10    float64 `json:"p10"`
+	P25    float64 `json:"p25"`
+	P75    float64 `json:"p75"`
+	P90    float64 `json:"p90"`
+}
+
+type DemographicsGrouping struct {
+	Gender  bool
+	Country bool
+}
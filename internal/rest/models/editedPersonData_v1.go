@@ -3,9 +3,12 @@ package models
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"strings"
 	"unicode/utf8"
+
+	"github.com/barpav/demography/internal/countries"
 )
 
 const MimeTypeEditedPersonDataV1 = "application/vnd.editedPersonData.v1+json"
@@ -28,33 +31,46 @@ func (m *EditedPersonDataV1) Deserialize(data io.Reader) error {
 	m.Surname = strings.TrimSpace(m.Surname)
 	m.Name = strings.TrimSpace(m.Name)
 	m.Patronymic = strings.TrimSpace(m.Patronymic)
+	m.Country = normalizedCountry(m.Country)
 
 	return m.validate()
 }
 
 func (m *EditedPersonDataV1) validate() (err error) {
 	if m.Surname == "" {
-		err = errors.Join(err, errors.New("Person's surname must be specified."))
+		err = errors.Join(err, fieldError("surname", "Person's surname must be specified."))
 	}
 
 	if utf8.RuneCountInString(m.Surname) > 150 {
-		err = errors.Join(err, errors.New("Person's surname cannot be greater than 150 characters."))
+		err = errors.Join(err, fieldError("surname", "Person's surname cannot be greater than 150 characters."))
 	}
 
 	if m.Name == "" {
-		err = errors.Join(err, errors.New("Person's name must be specified."))
+		err = errors.Join(err, fieldError("name", "Person's name must be specified."))
 	}
 
 	if utf8.RuneCountInString(m.Name) > 150 {
-		err = errors.Join(err, errors.New("Person's name cannot be greater than 150 characters."))
+		err = errors.Join(err, fieldError("name", "Person's name cannot be greater than 150 characters."))
 	}
 
 	if utf8.RuneCountInString(m.Patronymic) > 150 {
-		err = errors.Join(err, errors.New("Person's patronymic cannot be greater than 150 characters."))
+		err = errors.Join(err, fieldError("patronymic", "Person's patronymic cannot be greater than 150 characters."))
+	}
+
+	if m.Age < 0 {
+		err = errors.Join(err, fieldError("age", "Person's age cannot be negative."))
+	}
+
+	if m.Age > maxAge {
+		err = errors.Join(err, fieldError("age", fmt.Sprintf("Person's age cannot be greater than %d.", maxAge)))
+	}
+
+	if m.Gender != "" && !genderV1(m.Gender) {
+		err = errors.Join(err, fieldError("gender", "Incorrect gender value (enum)."))
 	}
 
-	if m.Gender != "" && m.Gender != "male" && m.Gender != "female" {
-		err = errors.Join(err, errors.New("Incorrect gender value (enum)."))
+	if m.Country != "" && !countries.Valid(m.Country) {
+		err = errors.Join(err, fieldError("country", "Country must be ISO 3166-1 alpha-2 code."))
 	}
 
 	return err
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/barpav/demography/internal/countries"
+)
+
+const MimeTypeEditedPersonDataV2 = "application/vnd.editedPersonData.v2+json"
+
+// Schema: editedPersonData.v2
+type EditedPersonDataV2 struct {
+	NewPersonDataV2
+	Age     int    `json:"age,omitempty"` // derived from birth date, if it's specified
+	Country string `json:"country,omitempty"`
+}
+
+func (m *EditedPersonDataV2) Deserialize(data io.Reader) error {
+	if json.NewDecoder(data).Decode(m) != nil {
+		return errors.New("Edited person data violates 'editedPersonData.v2' schema.")
+	}
+
+	m.normalize()
+	m.Country = normalizedCountry(m.Country)
+
+	err := errors.Join(m.NewPersonDataV2.validate(), m.validate())
+
+	if err != nil {
+		return err
+	}
+
+	if age, ok := m.NewPersonDataV2.Age(time.Now().UTC()); ok {
+		m.Age = age
+	}
+
+	return nil
+}
+
+func (m *EditedPersonDataV2) validate() (err error) {
+	if m.Age < 0 {
+		err = errors.Join(err, fieldError("age", "Person's age cannot be negative."))
+	}
+
+	if m.Age > maxAge {
+		err = errors.Join(err, fieldError("age", fmt.Sprintf("Person's age cannot be greater than %d.", maxAge)))
+	}
+
+	if age, ok := m.NewPersonDataV2.Age(time.Now().UTC()); ok && m.Age != 0 && m.Age != age {
+		err = errors.Join(err, fieldError("age", "Person's age must correspond to birth date."))
+	}
+
+	if m.Country != "" && !countries.Valid(m.Country) {
+		err = errors.Join(err, fieldError("country", "Country must be ISO 3166-1 alpha-2 code."))
+	}
+
+	return err
+}
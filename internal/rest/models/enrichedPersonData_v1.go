@@ -1,5 +1,7 @@
 package models
 
+import "strconv"
+
 const MimeTypeEnrichedPersonDataV1 = "application/vnd.enrichedPersonData.v1+json"
 
 // Schema: enrichedPersonData.v1
@@ -11,4 +13,27 @@ type EnrichedPersonDataV1 struct {
 	Age        int    `json:"age,omitempty"`
 	Gender     string `json:"gender,omitempty"`
 	Country    string `json:"country,omitempty"`
+	Version    int    `json:"-"` // ETag
+
+	CountryDetails *CountryV1 `json:"countryDetails,omitempty"` // expand=country
+}
+
+var EnrichedPersonDataV1CSVHeader = []string{"id", "surname", "name", "patronymic", "age", "gender", "country"}
+
+func (m *EnrichedPersonDataV1) CSVRecord() []string {
+	var age string
+
+	if m.Age != 0 {
+		age = strconv.Itoa(m.Age)
+	}
+
+	return []string{
+		strconv.FormatInt(m.Id, 10),
+		m.Surname,
+		m.Name,
+		m.Patronymic,
+		age,
+		m.Gender,
+		m.Country,
+	}
 }
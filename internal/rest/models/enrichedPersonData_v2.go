@@ -0,0 +1,22 @@
+package models
+
+const MimeTypeEnrichedPersonDataV2 = "application/vnd.enrichedPersonData.v2+json"
+
+// Schema: enrichedPersonData.v2
+type EnrichedPersonDataV2 struct {
+	Id            int64    `json:"id"`
+	Surname       string   `json:"surname"`
+	Name          string   `json:"name"`
+	Patronymic    string   `json:"patronymic,omitempty"`
+	MiddleNames   []string `json:"middleNames,omitempty"`
+	BirthDate     string   `json:"birthDate,omitempty"` // YYYY-MM-DD
+	Age           int      `json:"age,omitempty"`       // derived from birth date, if it's specified
+	Gender        string   `json:"gender,omitempty"`
+	GenderSource  string   `json:"genderSource,omitempty"` // statistics or declared
+	Country       string   `json:"country,omitempty"`
+	Nationalities []string `json:"nationalities,omitempty"`
+	Tags          []string `json:"tags,omitempty"`
+	Version       int      `json:"-"` // ETag
+
+	CountryDetails *CountryV1 `json:"countryDetails,omitempty"` // expand=country
+}
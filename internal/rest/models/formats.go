@@ -0,0 +1,9 @@
+package models
+
+// Generic formats, supported by some operations along with schema-specific ones.
+const (
+	MimeTypeCSV    = "text/csv"
+	MimeTypeNDJSON = "application/x-ndjson"
+
+	MimeTypeMergePatch = "application/merge-patch+json" // RFC 7396
+)
@@ -0,0 +1,22 @@
+package models
+
+const (
+	GenderMale    = "male"
+	GenderFemale  = "female"
+	GenderOther   = "other"
+	GenderUnknown = "unknown" // not determined by statistics or not disclosed by the person
+)
+
+const (
+	GenderSourceStatistics = "statistics"
+	GenderSourceDeclared   = "declared" // by the person
+)
+
+// Schemas of version 1 support only male and female genders.
+func genderV1(gender string) bool {
+	return gender == GenderMale || gender == GenderFemale
+}
+
+func gender(value string) bool {
+	return genderV1(value) || value == GenderOther || value == GenderUnknown
+}
@@ -0,0 +1,10 @@
+package models
+
+// Response to request with Idempotency-Key, replayed on retries of the same request.
+type IdempotentResponse struct {
+	Fingerprint []byte // of original request
+	Completed   bool   // false while original request is in progress
+	StatusCode  int
+	Header      map[string][]string
+	Body        []byte
+}
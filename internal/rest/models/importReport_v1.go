@@ -0,0 +1,17 @@
+package models
+
+const MimeTypeImportReportV1 = "application/vnd.importReport.v1+json"
+
+// Schema: importReport.v1
+type ImportReportV1 struct {
+	Created int              `json:"created"`
+	Failed  int              `json:"failed"`
+	Error   string           `json:"error,omitempty"` // import was interrupted, remaining records are not processed
+	Rows    []*ImportedRowV1 `json:"rows"`
+}
+
+type ImportedRowV1 struct {
+	Row    int      `json:"row"` // record number in imported data, starting from 1 (CSV header is not counted)
+	Id     int64    `json:"id,omitempty"`
+	Errors []string `json:"errors,omitempty"`
+}
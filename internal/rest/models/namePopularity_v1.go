@@ -0,0 +1,24 @@
+package models
+
+const MimeTypeNamePopularityV1 = "application/vnd.namePopularity.v1+json"
+
+// Schema: namePopularity.v1
+type NamePopularityV1 struct {
+	Total int64         `json:"total"` // people with specified field value matching filters
+	Data  []*NameRankV1 `json:"data,omitempty"`
+}
+
+type NameRankV1 struct {
+	Value string  `json:"value"`
+	Count int64   `json:"count"`
+	Share float64 `json:"share"` // percentage of total
+}
+
+// Person data field for name popularity statistics.
+type NameField string
+
+const (
+	NameFieldSurname    NameField = "surname"
+	NameFieldName       NameField = "name"
+	NameFieldPatronymic NameField = "patronymic"
+)
@@ -0,0 +1,10 @@
+package models
+
+const MimeTypeNameSummaryV1 = "application/vnd.nameSummary.v1+json"
+
+// Schema: nameSummary.v1
+type NameSummaryV1 struct {
+	Name       string  `json:"name"`
+	Count      int64   `json:"count"`
+	AverageAge float64 `json:"averageAge,omitempty"` // only people with known age are considered
+}
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const MimeTypeNewPeopleBatchV1 = "application/vnd.newPeopleBatch.v1+json"
+
+const (
+	BatchModeTransaction = "transaction" // all or nothing
+	BatchModeBestEffort  = "best-effort" // every item is processed independently
+)
+
+const maxBatchItems = 100
+
+// Schema: newPeopleBatch.v1
+type NewPeopleBatchV1 struct {
+	Mode  string
+	Items []*NewPersonDataV1
+}
+
+// Items are validated separately: batch is rejected only if it violates the schema itself.
+// Returned item errors correspond to batch items (nil for valid ones).
+func (m *NewPeopleBatchV1) Deserialize(data io.Reader) (itemErrors []error, err error) {
+	if json.NewDecoder(data).Decode(m) != nil {
+		return nil, errors.New("New people batch violates 'newPeopleBatch.v1' schema.")
+	}
+
+	err = m.validate()
+
+	if err != nil {
+		return nil, err
+	}
+
+	itemErrors = make([]error, len(m.Items))
+
+	for i, item := range m.Items {
+		if item == nil {
+			item = &NewPersonDataV1{}
+			m.Items[i] = item
+		}
+
+		item.normalize()
+		itemErrors[i] = item.validate()
+	}
+
+	return itemErrors, nil
+}
+
+func (m *NewPeopleBatchV1) validate() (err error) {
+	if m.Mode == "" {
+		m.Mode = BatchModeTransaction
+	}
+
+	if m.Mode != BatchModeTransaction && m.Mode != BatchModeBestEffort {
+		err = errors.Join(err, errors.New("Incorrect batch mode value (enum)."))
+	}
+
+	if len(m.Items) == 0 {
+		err = errors.Join(err, errors.New("Batch must contain at least one item."))
+	}
+
+	if len(m.Items) > maxBatchItems {
+		err = errors.Join(err, fmt.Errorf("Batch cannot contain more than %d items.", maxBatchItems))
+	}
+
+	return err
+}
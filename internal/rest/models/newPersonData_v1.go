@@ -22,32 +22,53 @@ func (m *NewPersonDataV1) Deserialize(data io.Reader) error {
 		return errors.New("New person data violates 'newPersonData.v1' schema.")
 	}
 
+	m.normalize()
+
+	return m.validate()
+}
+
+// Record fields: surname, name, patronymic (optional).
+func (m *NewPersonDataV1) DeserializeRecord(record []string) error {
+	if len(record) < 2 || len(record) > 3 {
+		return errors.New("New person data record must contain surname, name and optional patronymic.")
+	}
+
+	m.Surname, m.Name, m.Patronymic = record[0], record[1], ""
+
+	if len(record) == 3 {
+		m.Patronymic = record[2]
+	}
+
+	m.normalize()
+
+	return m.validate()
+}
+
+func (m *NewPersonDataV1) normalize() {
 	m.Surname = strings.TrimSpace(m.Surname)
 	m.Name = strings.TrimSpace(m.Name)
 	m.Patronymic = strings.TrimSpace(m.Patronymic)
-
-	return m.validate()
 }
 
 func (m *NewPersonDataV1) validate() (err error) {
 	if m.Surname == "" {
-		err = errors.Join(err, errors.New("Person's surname must be specified."))
+		err = errors.Join(err, fieldError("surname", "Person's surname must be specified."))
 	}
 
 	if utf8.RuneCountInString(m.Surname) > 150 {
-		err = errors.Join(err, errors.New("Person's surname cannot be greater than 150 characters."))
+		err = errors.Join(err, fieldError("surname", "Person's surname cannot be greater than 150 characters."))
 	}
 
 	if m.Name == "" {
-		err = errors.Join(err, errors.New("Person's name must be specified."))
+		err = errors.Join(err, fieldError("name", "Person's name must be specified."))
 	}
 
 	if utf8.RuneCountInString(m.Name) > 150 {
-		err = errors.Join(err, errors.New("Person's name cannot be greater than 150 characters."))
+		err = errors.Join(err, fieldError("name", "Person's name cannot be greater than 150 characters."))
 	}
 
 	if utf8.RuneCountInString(m.Patronymic) > 150 {
-		err = errors.Join(err, errors.New("Person's patronymic cannot be greater than 150 characters."))
+		err = errors.Join(err, fieldError("patronymic", "Person's patronymic cannot be greater than 150 characters."))
 	}
 
 	return err
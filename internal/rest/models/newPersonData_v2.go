@@ -0,0 +1,178 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/barpav/demography/internal/countries"
+)
+
+const MimeTypeNewPersonDataV2 = "application/vnd.newPersonData.v2+json"
+
+const (
+	DateFormat = "2006-01-02"
+
+	maxMiddleNames   = 10
+	maxNationalities = 10
+	maxTags          = 20
+	maxTagLength     = 50
+	maxAge           = 150
+)
+
+// Schema: newPersonData.v2
+type NewPersonDataV2 struct {
+	Surname       string   `json:"surname"`
+	Name          string   `json:"name"`
+	Patronymic    string   `json:"patronymic,omitempty"`
+	MiddleNames   []string `json:"middleNames,omitempty"`
+	BirthDate     string   `json:"birthDate,omitempty"`     // YYYY-MM-DD
+	Gender        string   `json:"gender,omitempty"`        // declared by the person, otherwise received from statistics
+	Nationalities []string `json:"nationalities,omitempty"` // ISO 3166-1 alpha-2 codes
+	Tags          []string `json:"tags,omitempty"`
+}
+
+func (m *NewPersonDataV2) Deserialize(data io.Reader) error {
+	if json.NewDecoder(data).Decode(m) != nil {
+		return errors.New("New person data violates 'newPersonData.v2' schema.")
+	}
+
+	m.normalize()
+
+	return m.validate()
+}
+
+// Returns age derived from birth date, if it's specified.
+func (m *NewPersonDataV2) Age(now time.Time) (age int, ok bool) {
+	birthDate, err := time.Parse(DateFormat, m.BirthDate)
+
+	if err != nil {
+		return 0, false
+	}
+
+	return AgeAt(birthDate, now), true
+}
+
+// Full years passed since birth date.
+func AgeAt(birthDate, now time.Time) int {
+	age := now.Year() - birthDate.Year()
+
+	if now.Month() < birthDate.Month() || now.Month() == birthDate.Month() && now.Day() < birthDate.Day() {
+		age--
+	}
+
+	return age
+}
+
+func (m *NewPersonDataV2) normalize() {
+	m.Surname = strings.TrimSpace(m.Surname)
+	m.Name = strings.TrimSpace(m.Name)
+	m.Patronymic = strings.TrimSpace(m.Patronymic)
+	m.BirthDate = strings.TrimSpace(m.BirthDate)
+	m.Gender = strings.TrimSpace(m.Gender)
+	m.MiddleNames = normalizedValues(m.MiddleNames, strings.TrimSpace)
+	m.Nationalities = normalizedValues(m.Nationalities, normalizedCountry)
+	m.Tags = normalizedValues(m.Tags, strings.TrimSpace)
+}
+
+func (m *NewPersonDataV2) validate() (err error) {
+	if m.Surname == "" {
+		err = errors.Join(err, fieldError("surname", "Person's surname must be specified."))
+	}
+
+	if utf8.RuneCountInString(m.Surname) > 150 {
+		err = errors.Join(err, fieldError("surname", "Person's surname cannot be greater than 150 characters."))
+	}
+
+	if m.Name == "" {
+		err = errors.Join(err, fieldError("name", "Person's name must be specified."))
+	}
+
+	if utf8.RuneCountInString(m.Name) > 150 {
+		err = errors.Join(err, fieldError("name", "Person's name cannot be greater than 150 characters."))
+	}
+
+	if utf8.RuneCountInString(m.Patronymic) > 150 {
+		err = errors.Join(err, fieldError("patronymic", "Person's patronymic cannot be greater than 150 characters."))
+	}
+
+	if len(m.MiddleNames) > maxMiddleNames {
+		err = errors.Join(err, fieldError("middleNames", fmt.Sprintf("Person cannot have more than %d middle names.", maxMiddleNames)))
+	}
+
+	for _, middleName := range m.MiddleNames {
+		if middleName == "" || utf8.RuneCountInString(middleName) > 150 {
+			err = errors.Join(err, fieldError("middleNames", "Person's middle name must contain from 1 to 150 characters."))
+			break
+		}
+	}
+
+	if m.BirthDate != "" {
+		birthDate, parseErr := time.Parse(DateFormat, m.BirthDate)
+		now := time.Now().UTC()
+
+		switch {
+		case parseErr != nil:
+			err = errors.Join(err, fieldError("birthDate", "Person's birth date must be in YYYY-MM-DD format."))
+		case birthDate.After(now):
+			err = errors.Join(err, fieldError("birthDate", "Person's birth date cannot be in the future."))
+		case AgeAt(birthDate, now) > maxAge:
+			err = errors.Join(err, fieldError("birthDate", fmt.Sprintf("Person's age cannot be greater than %d.", maxAge)))
+		}
+	}
+
+	if m.Gender != "" && !gender(m.Gender) {
+		err = errors.Join(err, fieldError("gender", "Incorrect gender value (enum)."))
+	}
+
+	if len(m.Nationalities) > maxNationalities {
+		err = errors.Join(err, fieldError("nationalities", fmt.Sprintf("Person cannot have more than %d nationalities.", maxNationalities)))
+	}
+
+	for _, code := range m.Nationalities {
+		if !countries.Valid(code) {
+			err = errors.Join(err, fieldError("nationalities", "Nationality must be ISO 3166-1 alpha-2 country code."))
+			break
+		}
+	}
+
+	if len(m.Tags) > maxTags {
+		err = errors.Join(err, fieldError("tags", fmt.Sprintf("Person cannot have more than %d tags.", maxTags)))
+	}
+
+	for _, tag := range m.Tags {
+		if tag == "" || utf8.RuneCountInString(tag) > maxTagLength {
+			err = errors.Join(err, fieldError("tags", fmt.Sprintf("Tag must contain from 1 to %d characters.", maxTagLength)))
+			break
+		}
+	}
+
+	return err
+}
+
+// Duplicates are removed, order is preserved.
+func normalizedValues(values []string, trim func(string) string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+
+	result := make([]string, 0, len(values))
+	seen := make(map[string]struct{}, len(values))
+
+	for _, v := range values {
+		v = trim(v)
+
+		if _, ok := seen[v]; ok {
+			continue
+		}
+
+		seen[v] = struct{}{}
+		result = append(result, v)
+	}
+
+	return result
+}
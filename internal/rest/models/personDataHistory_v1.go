@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+const MimeTypePersonDataHistoryV1 = "application/vnd.personDataHistory.v1+json"
+
+// Kinds of person data changes.
+const (
+	PersonDataChangeCreate  = "create"
+	PersonDataChangeUpdate  = "update"
+	PersonDataChangeDelete  = "delete"
+	PersonDataChangeRestore = "restore"
+	PersonDataChangePurge   = "purge"
+)
+
+// Schema: personDataHistory.v1
+type PersonDataHistoryV1 struct {
+	Total int                   `json:"total"`
+	Data  []*PersonDataChangeV1 `json:"data,omitempty"`
+}
+
+// Schema: personDataChange.v1
+type PersonDataChangeV1 struct {
+	Id        int64          `json:"id"`
+	Change    string         `json:"change"`
+	Version   int            `json:"version"` // of person data after change
+	Actor     string         `json:"actor,omitempty"`
+	ChangedAt time.Time      `json:"changedAt"`
+	Before    map[string]any `json:"before,omitempty"` // changed fields only
+	After     map[string]any `json:"after,omitempty"`  // changed fields only
+}
@@ -0,0 +1,136 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/barpav/demography/internal/countries"
+)
+
+// Schema: personDataPatch.v1 (JSON Merge Patch, RFC 7396).
+// Absent fields are not changed, null values remove optional data.
+type PersonDataPatchV1 struct {
+	Surname    PatchField[string]
+	Name       PatchField[string]
+	Patronymic PatchField[string]
+	Age        PatchField[int]
+	Gender     PatchField[string]
+	Country    PatchField[string]
+}
+
+type PatchField[T any] struct {
+	Set   bool // field is present in patch
+	Value *T   // nil if field is null
+}
+
+func (f *PatchField[T]) UnmarshalJSON(data []byte) error {
+	f.Set = true
+
+	if bytes.Equal(data, []byte("null")) {
+		f.Value = nil
+		return nil
+	}
+
+	f.Value = new(T)
+
+	return json.Unmarshal(data, f.Value)
+}
+
+func (m *PersonDataPatchV1) Deserialize(data io.Reader) error {
+	if json.NewDecoder(data).Decode(m) != nil {
+		return errors.New("Person data patch violates 'personDataPatch.v1' schema.")
+	}
+
+	for _, f := range []*PatchField[string]{&m.Surname, &m.Name, &m.Patronymic, &m.Country} {
+		if f.Value != nil {
+			*f.Value = strings.TrimSpace(*f.Value)
+		}
+	}
+
+	if m.Country.Value != nil {
+		*m.Country.Value = normalizedCountry(*m.Country.Value)
+	}
+
+	return m.validate()
+}
+
+func (m *PersonDataPatchV1) validate() (err error) {
+	if len(m.Changes()) == 0 {
+		err = errors.Join(err, errors.New("Patch must change at least one field."))
+	}
+
+	if m.Surname.Set && (m.Surname.Value == nil || *m.Surname.Value == "") {
+		err = errors.Join(err, fieldError("surname", "Person's surname cannot be removed."))
+	}
+
+	if m.Surname.Value != nil && utf8.RuneCountInString(*m.Surname.Value) > 150 {
+		err = errors.Join(err, fieldError("surname", "Person's surname cannot be greater than 150 characters."))
+	}
+
+	if m.Name.Set && (m.Name.Value == nil || *m.Name.Value == "") {
+		err = errors.Join(err, fieldError("name", "Person's name cannot be removed."))
+	}
+
+	if m.Name.Value != nil && utf8.RuneCountInString(*m.Name.Value) > 150 {
+		err = errors.Join(err, fieldError("name", "Person's name cannot be greater than 150 characters."))
+	}
+
+	if m.Patronymic.Value != nil && utf8.RuneCountInString(*m.Patronymic.Value) > 150 {
+		err = errors.Join(err, fieldError("patronymic", "Person's patronymic cannot be greater than 150 characters."))
+	}
+
+	if m.Age.Value != nil && *m.Age.Value < 0 {
+		err = errors.Join(err, fieldError("age", "Person's age cannot be negative."))
+	}
+
+	if m.Age.Value != nil && *m.Age.Value > maxAge {
+		err = errors.Join(err, fieldError("age", fmt.Sprintf("Person's age cannot be greater than %d.", maxAge)))
+	}
+
+	if m.Gender.Value != nil && !genderV1(*m.Gender.Value) {
+		err = errors.Join(err, fieldError("gender", "Incorrect gender value (enum)."))
+	}
+
+	if m.Country.Value != nil && *m.Country.Value != "" && !countries.Valid(*m.Country.Value) {
+		err = errors.Join(err, fieldError("country", "Country must be ISO 3166-1 alpha-2 code."))
+	}
+
+	return err
+}
+
+// Changed fields by schema names, nil values mean removal.
+// Empty strings and zero age are considered as removal too (as in editedPersonData.v1).
+func (m *PersonDataPatchV1) Changes() map[string]any {
+	changes := make(map[string]any)
+
+	for name, f := range map[string]*PatchField[string]{
+		"surname":    &m.Surname,
+		"name":       &m.Name,
+		"patronymic": &m.Patronymic,
+		"gender":     &m.Gender,
+		"country":    &m.Country,
+	} {
+		if f.Set {
+			changes[name] = nil
+
+			if f.Value != nil && *f.Value != "" {
+				changes[name] = *f.Value
+			}
+		}
+	}
+
+	if m.Age.Set {
+		changes["age"] = nil
+
+		if m.Age.Value != nil && *m.Age.Value != 0 {
+			changes["age"] = *m.Age.Value
+		}
+	}
+
+	return changes
+}
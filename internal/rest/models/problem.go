@@ -0,0 +1,63 @@
+package models
+
+import "errors"
+
+const MimeTypeProblem = "application/problem+json" // RFC 7807
+
+const problemTypeBase = "https://barpav.github.io/demography-api/problems/"
+
+// Problem types. Default one means that problem is fully described by status code.
+const (
+	ProblemTypeDefault             = "about:blank"
+	ProblemTypeValidationError     = problemTypeBase + "validation-error"
+	ProblemTypeConstraintViolation = problemTypeBase + "constraint-violation"
+	ProblemTypeIdempotencyKeyInUse = problemTypeBase + "idempotency-key-in-use"
+	ProblemTypeIdempotencyKeyReuse = problemTypeBase + "idempotency-key-reuse"
+)
+
+// Schema: problem (RFC 7807)
+type Problem struct {
+	Type          string          `json:"type"`
+	Title         string          `json:"title"`
+	Status        int             `json:"status"`
+	Detail        string          `json:"detail,omitempty"`
+	InvalidParams []*InvalidParam `json:"invalid-params,omitempty"`
+}
+
+type InvalidParam struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+// Validation error of the specific field (schema field name).
+type FieldError struct {
+	Field  string
+	Reason string
+}
+
+func fieldError(field, reason string) error {
+	return &FieldError{Field: field, Reason: reason}
+}
+
+func (e *FieldError) Error() string {
+	return e.Reason
+}
+
+// Collects field errors from (possibly joined) validation error.
+func InvalidParams(err error) (params []*InvalidParam) {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, e := range joined.Unwrap() {
+			params = append(params, InvalidParams(e)...)
+		}
+
+		return params
+	}
+
+	var fieldErr *FieldError
+
+	if errors.As(err, &fieldErr) {
+		params = append(params, &InvalidParam{Name: fieldErr.Field, Reason: fieldErr.Reason})
+	}
+
+	return params
+}
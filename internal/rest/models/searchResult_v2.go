@@ -0,0 +1,9 @@
+package models
+
+const MimeTypeSearchResultV2 = "application/vnd.searchResult.v2+json"
+
+// Schema: searchResult.v2
+type SearchResultV2 struct {
+	Total int                     `json:"total"`
+	Data  []*EnrichedPersonDataV2 `json:"data,omitempty"`
+}
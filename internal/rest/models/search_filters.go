@@ -1,12 +1,17 @@
 package models
 
+import "time"
+
 type SearchFilters struct {
-	Surname    string
-	Name       string
-	Patronymic string
-	Age        int
-	Gender     string
-	Country    string
-	After      int64
-	Limit      int
+	Surname     string
+	Name        string
+	Patronymic  string
+	Age         int
+	Gender      string
+	Country     string
+	After       int64
+	Limit       int
+	Translit    bool      // match names in both Cyrillic and Latin scripts
+	AsOf        time.Time // state of data at the moment, current if zero
+	WithDeleted bool      // at the moment, including people deleted since then
 }
@@ -0,0 +1,135 @@
+// Package negotiation selects media types of request and response bodies
+// according to Content-Type and Accept headers (RFC 9110, section 12).
+package negotiation
+
+import (
+	"mime"
+	"strconv"
+	"strings"
+)
+
+// Match specificity of media range: */* < type/* < type/structured-suffix < type/subtype.
+const (
+	matchAny = iota + 1
+	matchType
+	matchSuffix
+	matchExact
+)
+
+type mediaRange struct {
+	typ, subtype string
+	q            float64
+}
+
+// Accept returns the most acceptable of offered media types (in server preference order),
+// or empty string if none of them is acceptable. Missing header means that any type is acceptable.
+// Parameters of media ranges other than q are ignored. Range with structured syntax suffix,
+// e.g. application/json, matches offers with the same suffix, e.g. application/vnd.x.v1+json.
+func Accept(header string, offers ...string) string {
+	if strings.TrimSpace(header) == "" {
+		if len(offers) == 0 {
+			return ""
+		}
+
+		return offers[0]
+	}
+
+	ranges := parseAccept(header)
+	best, bestQ := "", 0.0
+
+	for _, offer := range offers {
+		typ, subtype, ok := splitMediaType(offer)
+
+		if !ok {
+			continue
+		}
+
+		if q := quality(ranges, typ, subtype); q > bestQ {
+			best, bestQ = offer, q
+		}
+	}
+
+	return best
+}
+
+// ContentType returns supported media type specified by Content-Type header, or empty string
+// if it's not supported or malformed. Media types are compared case-insensitively, parameters are ignored.
+func ContentType(header string, supported ...string) string {
+	mediaType, _, err := mime.ParseMediaType(header)
+
+	if err != nil {
+		return ""
+	}
+
+	for _, s := range supported {
+		if strings.EqualFold(mediaType, s) {
+			return s
+		}
+	}
+
+	return ""
+}
+
+// Quality of the most specific media range matching media type.
+func quality(ranges []mediaRange, typ, subtype string) float64 {
+	specificity, q := 0, 0.0
+
+	for _, r := range ranges {
+		if m := r.match(typ, subtype); m > specificity {
+			specificity, q = m, r.q
+		}
+	}
+
+	return q
+}
+
+func (r mediaRange) match(typ, subtype string) int {
+	switch {
+	case r.typ == "*" && r.subtype == "*":
+		return matchAny
+	case r.typ != typ:
+		return 0
+	case r.subtype == "*":
+		return matchType
+	case r.subtype == subtype:
+		return matchExact
+	case strings.HasSuffix(subtype, "+"+r.subtype):
+		return matchSuffix
+	}
+
+	return 0
+}
+
+func parseAccept(header string) (ranges []mediaRange) {
+	for _, part := range strings.Split(header, ",") {
+		mediaType, params, err := mime.ParseMediaType(part)
+
+		if err != nil {
+			continue
+		}
+
+		r := mediaRange{q: 1}
+		var ok bool
+
+		if r.typ, r.subtype, ok = splitMediaType(mediaType); !ok {
+			continue
+		}
+
+		if qValue, found := params["q"]; found {
+			r.q, err = strconv.ParseFloat(qValue, 64)
+
+			if err != nil || r.q < 0 || r.q > 1 {
+				continue
+			}
+		}
+
+		ranges = append(ranges, r)
+	}
+
+	return ranges
+}
+
+func splitMediaType(mediaType string) (typ, subtype string, ok bool) {
+	typ, subtype, ok = strings.Cut(strings.ToLower(mediaType), "/")
+	return typ, subtype, ok && typ != "" && subtype != ""
+}
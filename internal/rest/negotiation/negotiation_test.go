@@ -0,0 +1,59 @@
+package negotiation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccept(t *testing.T) {
+	offers := []string{"application/vnd.searchResult.v1+json", "text/csv", "application/x-ndjson"}
+
+	tests := []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{name: "Missing header", header: "", want: "application/vnd.searchResult.v1+json"},
+		{name: "Exact match", header: "text/csv", want: "text/csv"},
+		{name: "Case insensitive", header: "application/VND.SEARCHRESULT.V1+JSON", want: "application/vnd.searchResult.v1+json"},
+		{name: "Parameters", header: "text/csv; charset=utf-8", want: "text/csv"},
+		{name: "Structured suffix", header: "application/json, */*;q=0.8", want: "application/vnd.searchResult.v1+json"},
+		{name: "Any", header: "*/*", want: "application/vnd.searchResult.v1+json"},
+		{name: "Type wildcard", header: "text/*", want: "text/csv"},
+		{name: "Q-values", header: "application/json;q=0.5, application/x-ndjson", want: "application/x-ndjson"},
+		{name: "Most specific range wins", header: "*/*, text/csv;q=0", want: "application/vnd.searchResult.v1+json"},
+		{name: "Not acceptable", header: "application/xml", want: ""},
+		{name: "Zero quality", header: "text/csv;q=0", want: ""},
+		{name: "Malformed ranges skipped", header: "text, text/csv;q=2, application/x-ndjson", want: "application/x-ndjson"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, Accept(tt.header, offers...))
+		})
+	}
+}
+
+func TestContentType(t *testing.T) {
+	supported := []string{"application/vnd.newPersonData.v1+json", "text/csv"}
+
+	tests := []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{name: "Exact match", header: "text/csv", want: "text/csv"},
+		{name: "Parameters", header: "application/vnd.newPersonData.v1+json; charset=utf-8", want: "application/vnd.newPersonData.v1+json"},
+		{name: "Case insensitive", header: "Application/Vnd.NewPersonData.V1+Json", want: "application/vnd.newPersonData.v1+json"},
+		{name: "Not supported", header: "application/json", want: ""},
+		{name: "Missing", header: "", want: ""},
+		{name: "Malformed", header: "text/csv; charset", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, ContentType(tt.header, supported...))
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/barpav/demography/internal/rest/models"
+	"github.com/barpav/demography/internal/rest/negotiation"
+)
+
+// https://barpav.github.io/demography-api/#/people/patch_people
+func (s *Service) patchPeople(w http.ResponseWriter, r *http.Request) {
+	if negotiation.ContentType(r.Header.Get("Content-Type"), models.MimeTypeMergePatch) == "" {
+		respondWithProblem(w, http.StatusUnsupportedMediaType)
+		return
+	}
+
+	switch negotiation.Accept(r.Header.Get("Accept"), models.MimeTypeBulkOperationV1) {
+	case models.MimeTypeBulkOperationV1:
+		s.patchPeopleV1(w, r)
+	default:
+		respondWithProblem(w, http.StatusNotAcceptable)
+		return
+	}
+}
+
+func (s *Service) patchPeopleV1(w http.ResponseWriter, r *http.Request) {
+	filters, err := bulkFilters(r)
+
+	if err != nil {
+		respondWithInvalidRequest(w, err)
+		return
+	}
+
+	patch := &models.PersonDataPatchV1{}
+	err = patch.Deserialize(r.Body)
+
+	if err != nil {
+		respondWithInvalidRequest(w, err)
+		return
+	}
+
+	s.performBulkOperationV1(w, r, &bulkOperation{
+		name:    "update",
+		filters: filters,
+		patch:   patch,
+		execute: func(expected int64) (int64, error) {
+			return s.storage.PatchPeopleDataV1(r.Context(), filters, patch, expected)
+		},
+	})
+}
@@ -0,0 +1,156 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/barpav/demography/internal/rest/mocks"
+	"github.com/barpav/demography/internal/rest/models"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_patchPeople(t *testing.T) {
+	cfg := &config{bulkTokenSecret: []byte("test")}
+	patch := func(data string) *models.PersonDataPatchV1 {
+		p := &models.PersonDataPatchV1{}
+		require.NoError(t, p.Deserialize(strings.NewReader(data)))
+		return p
+	}
+	token := func(filters *models.SearchFilters, patch *models.PersonDataPatchV1) string {
+		s := &Service{cfg: cfg}
+		op := &bulkOperation{name: "update", filters: filters, patch: patch}
+		token, err := s.bulkConfirmationToken(op, 3, time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		return url.QueryEscape(token)
+	}
+	request := func(target, patch string) *http.Request {
+		r := httptest.NewRequest("PATCH", target, strings.NewReader(patch))
+		r.Header.Set("Content-Type", models.MimeTypeMergePatch)
+		return r
+	}
+
+	type testService struct {
+		storage Storage
+	}
+	type args struct {
+		w *httptest.ResponseRecorder
+		r *http.Request
+	}
+	tests := []struct {
+		name        string
+		testService testService
+		args        args
+		wantHeaders map[string]string
+		wantDryRun  bool
+		wantCount   int64
+		wantStatus  int
+	}{
+		{
+			name: "Dry run (200)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: request("/v1/people?surname=Ivanov", `{"country": "RU"}`),
+			},
+			testService: testService{
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("CountPeople", mock.Anything, &models.SearchFilters{Surname: "Ivanov"}).Return(int64(3), nil)
+					return s
+				}(),
+			},
+			wantHeaders: map[string]string{
+				"Content-Type": models.MimeTypeBulkOperationV1,
+			},
+			wantDryRun: true,
+			wantCount:  3,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "Confirmed (200)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: request("/v1/people?surname=Ivanov&confirm="+
+					token(&models.SearchFilters{Surname: "Ivanov"}, patch(`{"country": "RU", "age": null}`)),
+					`{"age": null, "country": "RU"}`),
+			},
+			testService: testService{
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("PatchPeopleDataV1", mock.Anything, &models.SearchFilters{Surname: "Ivanov"},
+						patch(`{"country": "RU", "age": null}`), int64(3)).Return(int64(3), nil)
+					return s
+				}(),
+			},
+			wantHeaders: map[string]string{
+				"Content-Type": models.MimeTypeBulkOperationV1,
+			},
+			wantCount:  3,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "Token for other patch (400)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: request("/v1/people?surname=Ivanov&confirm="+
+					token(&models.SearchFilters{Surname: "Ivanov"}, patch(`{"country": "RU"}`)),
+					`{"country": "KZ"}`),
+			},
+			wantHeaders: map[string]string{},
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			name: "Incorrect patch (400)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: request("/v1/people?surname=Ivanov", `{"surname": null, "gender": "unknown"}`),
+			},
+			wantHeaders: map[string]string{},
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			name: "Unsupported patch (415)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					r := request("/v1/people?surname=Ivanov", `{"country": "RU"}`)
+					r.Header.Set("Content-Type", "application/json")
+					return r
+				}(),
+			},
+			wantHeaders: map[string]string{},
+			wantStatus:  http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Service{
+				cfg:     cfg,
+				storage: tt.testService.storage,
+			}
+			s.patchPeople(tt.args.w, tt.args.r)
+
+			for k, v := range tt.wantHeaders {
+				require.Equal(t, v, tt.args.w.Result().Header.Get(k))
+			}
+
+			require.Equal(t, tt.wantStatus, tt.args.w.Code)
+
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			body := models.BulkOperationV1{}
+			err := json.NewDecoder(tt.args.w.Body).Decode(&body)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantDryRun, body.DryRun)
+			require.Equal(t, tt.wantCount, body.Affected)
+		})
+	}
+}
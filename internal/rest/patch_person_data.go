@@ -0,0 +1,73 @@
+package rest
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/barpav/demography/internal/rest/models"
+	"github.com/barpav/demography/internal/rest/negotiation"
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+)
+
+// https://barpav.github.io/demography-api/#/people/patch_people__id_
+func (s *Service) patchPersonData(w http.ResponseWriter, r *http.Request) {
+	switch negotiation.ContentType(r.Header.Get("Content-Type"), models.MimeTypeMergePatch) {
+	case models.MimeTypeMergePatch:
+		s.patchPersonDataV1(w, r)
+	default:
+		respondWithProblem(w, http.StatusUnsupportedMediaType)
+		return
+	}
+}
+
+func (s *Service) patchPersonDataV1(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 0)
+
+	if err != nil {
+		respondWithProblem(w, http.StatusNotFound)
+		return
+	}
+
+	versions, ok := s.expectedVersions(w, r)
+
+	if !ok {
+		return
+	}
+
+	patch := models.PersonDataPatchV1{}
+	err = patch.Deserialize(r.Body)
+
+	if err != nil {
+		respondWithInvalidRequest(w, err)
+		return
+	}
+
+	version, err := s.storage.PatchPersonDataV1(r.Context(), id, &patch, versions)
+
+	if err != nil {
+		if _, ok := err.(ErrPersonDataNotFound); ok {
+			respondWithProblem(w, http.StatusNotFound)
+			return
+		}
+
+		if _, ok := err.(ErrPersonDataVersionMismatch); ok {
+			respondWithProblemDetail(w, http.StatusPreconditionFailed, "Person data has been changed since ETag was received.")
+			return
+		}
+
+		if violation, ok := err.(ErrPersonDataConstraintViolation); ok {
+			respondWithConstraintViolation(w, violation)
+			return
+		}
+
+		log.Err(err).Msg("Failed to patch person data (v1).")
+		respondWithProblem(w, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("ETag", entityTag(version))
+
+	log.Info().Msg(fmt.Sprintf("Person data with id '%d' patched.", id))
+}
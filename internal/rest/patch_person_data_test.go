@@ -0,0 +1,168 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/barpav/demography/internal/rest/mocks"
+	"github.com/barpav/demography/internal/rest/models"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_patchPersonData(t *testing.T) {
+	age := 42
+	cfg := &config{}
+	request := func(id, patch, contentType string) *http.Request {
+		r := httptest.NewRequest("PATCH", "/v1/people/{id}", strings.NewReader(patch))
+		r.Header.Set("Content-Type", contentType)
+		ctx := chi.NewRouteContext()
+		ctx.URLParams.Add("id", id)
+		return r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, ctx))
+	}
+
+	type testService struct {
+		storage Storage
+	}
+	type args struct {
+		w *httptest.ResponseRecorder
+		r *http.Request
+	}
+	tests := []struct {
+		name        string
+		testService testService
+		args        args
+		wantStatus  int
+	}{
+		{
+			name: "Patched (200)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: request("101", `{"age": 42, "gender": null}`, models.MimeTypeMergePatch),
+			},
+			testService: testService{
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("PatchPersonDataV1", mock.Anything, int64(101),
+						&models.PersonDataPatchV1{
+							Age:    models.PatchField[int]{Set: true, Value: &age},
+							Gender: models.PatchField[string]{Set: true},
+						},
+						[]int(nil),
+					).Return(2, nil)
+					return s
+				}(),
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "Mandatory data removed (400)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: request("101", `{"name": null}`, models.MimeTypeMergePatch),
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Unrealistic age (400)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: request("101", `{"age": 151}`, models.MimeTypeMergePatch),
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Empty patch (400)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: request("101", `{"test": "test value"}`, models.MimeTypeMergePatch),
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Person not found in DB (404)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: request("101", `{"age": 42}`, models.MimeTypeMergePatch),
+			},
+			testService: testService{
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("PatchPersonDataV1", mock.Anything, int64(101), mock.Anything, []int(nil)).Return(0, ErrPersonDataNotFoundTest{})
+					return s
+				}(),
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name: "Person data version mismatch (412)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					r := request("101", `{"age": 42}`, models.MimeTypeMergePatch)
+					r.Header.Set("If-Match", `"7"`)
+					return r
+				}(),
+			},
+			testService: testService{
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("PatchPersonDataV1", mock.Anything, int64(101), mock.Anything, []int{7}).Return(0, ErrPersonDataVersionMismatchTest{})
+					return s
+				}(),
+			},
+			wantStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name: "Data constraint violation (422)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: request("101", `{"age": 42}`, models.MimeTypeMergePatch),
+			},
+			testService: testService{
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("PatchPersonDataV1", mock.Anything, int64(101), mock.Anything, []int(nil)).Return(0, ErrPersonDataConstraintViolationTest{})
+					return s
+				}(),
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "Person not found - bad id (404)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: request("some-text", `{"age": 42}`, models.MimeTypeMergePatch),
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name: "Unsupported patch (415)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: request("101", `{"age": 42}`, "application/json"),
+			},
+			wantStatus: http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Service{
+				storage: tt.testService.storage,
+				cfg:     cfg,
+			}
+			s.patchPersonData(tt.args.w, tt.args.r)
+
+			require.Equal(t, tt.wantStatus, tt.args.w.Code)
+
+			if tt.wantStatus == http.StatusOK {
+				require.Equal(t, `"2"`, tt.args.w.Result().Header.Get("ETag"))
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package rest
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Person data version is used as strong entity tag, e.g. "3".
+func entityTag(version int) string {
+	return fmt.Sprintf(`"%d"`, version)
+}
+
+// Returns versions of person data expected by client (If-Match), any of them matches the current one.
+// No versions means unconditional change. If precondition can't be satisfied, response is written and ok is false.
+func (s *Service) expectedVersions(w http.ResponseWriter, r *http.Request) (versions []int, ok bool) {
+	ifMatch := strings.TrimSpace(r.Header.Get("If-Match"))
+
+	switch ifMatch {
+	case "":
+		if s.cfg.requireIfMatch {
+			respondWithProblemDetail(w, http.StatusPreconditionRequired, "If-Match header with person data ETag is required.")
+			return nil, false
+		}
+
+		return nil, true
+	case "*":
+		return nil, true
+	}
+
+	// Strong comparison: weak tags never match the current version.
+	for _, tag := range strings.Split(ifMatch, ",") {
+		version, err := versionFromEntityTag(strings.TrimSpace(tag))
+
+		if err == nil && version > 0 {
+			versions = append(versions, version)
+		}
+	}
+
+	if len(versions) == 0 {
+		respondWithProblemDetail(w, http.StatusPreconditionFailed, "If-Match must contain strong ETags of person data.")
+		return nil, false
+	}
+
+	return versions, true
+}
+
+// Weak comparison (If-None-Match), e.g. "3", W/"3", "2", "3" or *.
+func notModified(r *http.Request, version int) bool {
+	ifNoneMatch := r.Header.Get("If-None-Match")
+
+	if ifNoneMatch == "" {
+		return false
+	}
+
+	for _, tag := range strings.Split(ifNoneMatch, ",") {
+		tag = strings.TrimSpace(tag)
+
+		if tag == "*" {
+			return true
+		}
+
+		v, err := versionFromEntityTag(strings.TrimPrefix(tag, "W/"))
+
+		if err == nil && v == version {
+			return true
+		}
+	}
+
+	return false
+}
+
+func versionFromEntityTag(tag string) (int, error) {
+	if len(tag) < 2 || !strings.HasPrefix(tag, `"`) || !strings.HasSuffix(tag, `"`) {
+		return 0, fmt.Errorf("invalid entity tag: %s", tag)
+	}
+
+	return strconv.Atoi(tag[1 : len(tag)-1])
+}
+
+type ErrPersonDataVersionMismatch interface {
+	Error() string
+	ImplementsPersonDataVersionMismatchError()
+}
@@ -0,0 +1,70 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/barpav/demography/internal/rest/models"
+	"github.com/rs/zerolog/log"
+)
+
+// Error response fully described by status code.
+func respondWithProblem(w http.ResponseWriter, status int) {
+	respondWithProblemDetail(w, status, "")
+}
+
+func respondWithProblemDetail(w http.ResponseWriter, status int, detail string) {
+	writeProblem(w, &models.Problem{
+		Type:   models.ProblemTypeDefault,
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+	})
+}
+
+// Field errors of request data are listed as invalid params.
+func respondWithInvalidRequest(w http.ResponseWriter, err error) {
+	problem := &models.Problem{
+		Type:   models.ProblemTypeDefault,
+		Title:  http.StatusText(http.StatusBadRequest),
+		Status: http.StatusBadRequest,
+		Detail: err.Error(),
+	}
+
+	if params := models.InvalidParams(err); len(params) != 0 {
+		problem.Type = models.ProblemTypeValidationError
+		problem.Title = "Request data is invalid."
+		problem.InvalidParams = params
+	}
+
+	writeProblem(w, problem)
+}
+
+// Request data is valid, but violates constraints of stored data.
+func respondWithConstraintViolation(w http.ResponseWriter, err ErrPersonDataConstraintViolation) {
+	problem := &models.Problem{
+		Type:   models.ProblemTypeConstraintViolation,
+		Title:  "Request data violates data constraints.",
+		Status: http.StatusUnprocessableEntity,
+	}
+
+	if name, reason := err.InvalidField(); name != "" {
+		problem.InvalidParams = []*models.InvalidParam{{Name: name, Reason: reason}}
+	} else {
+		problem.Detail = reason
+	}
+
+	writeProblem(w, problem)
+}
+
+func writeProblem(w http.ResponseWriter, problem *models.Problem) {
+	w.Header().Set("Content-Type", models.MimeTypeProblem)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(problem.Status)
+
+	err := json.NewEncoder(w).Encode(problem)
+
+	if err != nil {
+		log.Err(err).Msg("Failed to serialize problem details.")
+	}
+}
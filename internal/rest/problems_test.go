@@ -0,0 +1,91 @@
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/barpav/demography/internal/rest/models"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_respondWithInvalidRequest(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want *models.Problem
+	}{
+		{
+			name: "Field errors",
+			err: func() error {
+				data := models.EditedPersonDataV1{}
+				return data.Deserialize(strings.NewReader(`{"name": "Ivan", "gender": "test"}`))
+			}(),
+			want: &models.Problem{
+				Type:   models.ProblemTypeValidationError,
+				Title:  "Request data is invalid.",
+				Status: http.StatusBadRequest,
+				Detail: "Person's surname must be specified.\nIncorrect gender value (enum).",
+				InvalidParams: []*models.InvalidParam{
+					{Name: "surname", Reason: "Person's surname must be specified."},
+					{Name: "gender", Reason: "Incorrect gender value (enum)."},
+				},
+			},
+		},
+		{
+			name: "Schema violation",
+			err:  errors.New("Edited person data violates 'editedPersonData.v1' schema."),
+			want: &models.Problem{
+				Type:   models.ProblemTypeDefault,
+				Title:  "Bad Request",
+				Status: http.StatusBadRequest,
+				Detail: "Edited person data violates 'editedPersonData.v1' schema.",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			respondWithInvalidRequest(w, tt.err)
+
+			require.Equal(t, http.StatusBadRequest, w.Code)
+			require.Equal(t, models.MimeTypeProblem, w.Result().Header.Get("Content-Type"))
+
+			problem := &models.Problem{}
+			err := json.NewDecoder(w.Body).Decode(problem)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			require.Equal(t, tt.want, problem)
+		})
+	}
+}
+
+func Test_respondWithProblem(t *testing.T) {
+	w := httptest.NewRecorder()
+	respondWithProblem(w, http.StatusNotFound)
+
+	require.Equal(t, http.StatusNotFound, w.Code)
+	require.Equal(t, models.MimeTypeProblem, w.Result().Header.Get("Content-Type"))
+	require.JSONEq(t, `{"type": "about:blank", "title": "Not Found", "status": 404}`, w.Body.String())
+}
+
+func Test_respondWithConstraintViolation(t *testing.T) {
+	w := httptest.NewRecorder()
+	respondWithConstraintViolation(w, ErrPersonDataConstraintViolationTest{})
+
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	require.Equal(t, models.MimeTypeProblem, w.Result().Header.Get("Content-Type"))
+	require.JSONEq(t, `{
+		"type": "https://barpav.github.io/demography-api/problems/constraint-violation",
+		"title": "Request data violates data constraints.",
+		"status": 422,
+		"invalid-params": [{"name": "age", "reason": "Person's age must be between 0 and 150."}]
+	}`, w.Body.String())
+}
@@ -0,0 +1,170 @@
+package rest
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/barpav/demography/internal/actor"
+	"github.com/rs/zerolog/log"
+)
+
+// Idle buckets are forgotten after they are refilled.
+const rateLimitSweepInterval = time.Minute
+
+// Token bucket per client: every request (or record, see Service.takeRecords) takes a token,
+// tokens are refilled evenly so that full bucket (burst) is restored in a minute.
+type rateLimiter struct {
+	perMinute int
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	swept     time.Time
+}
+
+type tokenBucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// Zero or negative limit means no limit.
+func newRateLimiter(perMinute int) *rateLimiter {
+	if perMinute <= 0 {
+		return nil
+	}
+
+	return &rateLimiter{perMinute: perMinute, buckets: make(map[string]*tokenBucket)}
+}
+
+// Returns remaining tokens and time until the bucket is full again.
+// If request is not allowed, retryAfter is time until the bucket has enough tokens.
+func (l *rateLimiter) take(client string, tokens int, now time.Time) (allowed bool, remaining int, reset, retryAfter time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(now)
+
+	b, found := l.buckets[client]
+
+	if !found {
+		b = &tokenBucket{tokens: float64(l.perMinute), updated: now}
+		l.buckets[client] = b
+	}
+
+	b.refill(now, l.rate(), float64(l.perMinute))
+
+	if b.tokens >= float64(tokens) {
+		b.tokens -= float64(tokens)
+		allowed = true
+	} else {
+		retryAfter = l.duration(float64(tokens) - b.tokens)
+	}
+
+	return allowed, int(b.tokens), l.duration(float64(l.perMinute) - b.tokens), retryAfter
+}
+
+func (l *rateLimiter) sweep(now time.Time) {
+	if now.Sub(l.swept) < rateLimitSweepInterval {
+		return
+	}
+
+	for client, b := range l.buckets {
+		if now.Sub(b.updated) >= time.Minute {
+			delete(l.buckets, client)
+		}
+	}
+
+	l.swept = now
+}
+
+// Tokens per second.
+func (l *rateLimiter) rate() float64 {
+	return float64(l.perMinute) / 60
+}
+
+// Time to refill the tokens.
+func (l *rateLimiter) duration(tokens float64) time.Duration {
+	return time.Duration(tokens / l.rate() * float64(time.Second))
+}
+
+func (b *tokenBucket) refill(now time.Time, rate, capacity float64) {
+	if elapsed := now.Sub(b.updated).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(capacity, b.tokens+elapsed*rate)
+		b.updated = now
+	}
+}
+
+// Clients (API keys, users or network addresses, see Service.authenticate) have separate budgets
+// for reading (GET, HEAD) and changing data. Limits are reported in RateLimit-* headers
+// (https://datatracker.ietf.org/doc/draft-ietf-httpapi-ratelimit-headers/).
+func (s *Service) limitRate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limiter := s.writeLimits
+
+		if r.Method == http.MethodGet || r.Method == http.MethodHead {
+			limiter = s.readLimits
+		}
+
+		if !takeTokens(w, limiter, actor.FromContext(r.Context()), 1) {
+			respondWithRateLimitExceeded(w, limiter, "requests")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Every network address has the budget for all requests, including the ones with invalid credentials,
+// which are rejected by Service.authenticate and otherwise could be repeated for free.
+func (s *Service) limitAddressRate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !takeTokens(w, s.addressLimits, clientAddress(r), 1) {
+			respondWithRateLimitExceeded(w, s.addressLimits, "requests")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Records of batches and imports are enriched by 3rd party statistics APIs one by one,
+// so clients have separate budget for them taken in addition to the request.
+func (s *Service) takeRecords(w http.ResponseWriter, r *http.Request, records int) bool {
+	return records == 0 || takeTokens(w, s.recordLimits, actor.FromContext(r.Context()), records)
+}
+
+// Returns false if the client has exceeded the limit (nil limiter means no limit).
+func takeTokens(w http.ResponseWriter, limiter *rateLimiter, client string, tokens int) bool {
+	if limiter == nil {
+		return true
+	}
+
+	allowed, remaining, reset, retryAfter := limiter.take(client, tokens, time.Now())
+
+	w.Header().Set("RateLimit-Limit", strconv.Itoa(limiter.perMinute))
+	w.Header().Set("RateLimit-Remaining", strconv.Itoa(remaining))
+	w.Header().Set("RateLimit-Reset", strconv.Itoa(seconds(reset)))
+	w.Header().Set("RateLimit-Policy", fmt.Sprintf("%d;w=60", limiter.perMinute))
+
+	if !allowed {
+		log.Info().Msg(fmt.Sprintf("Rate limit exceeded by '%s'.", client))
+		w.Header().Set("Retry-After", strconv.Itoa(seconds(retryAfter)))
+	}
+
+	return allowed
+}
+
+func respondWithRateLimitExceeded(w http.ResponseWriter, limiter *rateLimiter, units string) {
+	respondWithProblemDetail(w, http.StatusTooManyRequests, rateLimitExceeded(limiter, units))
+}
+
+func rateLimitExceeded(limiter *rateLimiter, units string) string {
+	return fmt.Sprintf("Rate limit of %d %s per minute exceeded.", limiter.perMinute, units)
+}
+
+// Rounded up to whole seconds.
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,102 @@
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/barpav/demography/internal/actor"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiter_take(t *testing.T) {
+	require.Nil(t, newRateLimiter(0))
+
+	l := newRateLimiter(2) // token every 30 seconds
+	now := time.Date(2023, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	allowed, remaining, reset, _ := l.take("api-key:reporting", 1, now)
+	require.True(t, allowed)
+	require.Equal(t, 1, remaining)
+	require.Equal(t, 30*time.Second, reset)
+
+	allowed, remaining, _, _ = l.take("api-key:reporting", 1, now)
+	require.True(t, allowed)
+	require.Equal(t, 0, remaining)
+
+	allowed, _, _, retryAfter := l.take("api-key:reporting", 1, now.Add(10*time.Second))
+	require.False(t, allowed)
+	require.Equal(t, 20*time.Second, retryAfter)
+
+	allowed, _, _, _ = l.take("address:192.0.2.1", 1, now.Add(10*time.Second))
+	require.True(t, allowed, "separate budget")
+
+	allowed, remaining, _, _ = l.take("api-key:reporting", 1, now.Add(30*time.Second))
+	require.True(t, allowed)
+	require.Equal(t, 0, remaining)
+
+	allowed, _, _, retryAfter = l.take("api-key:reporting", 2, now.Add(30*time.Second))
+	require.False(t, allowed, "several tokens at once")
+	require.Equal(t, 60*time.Second, retryAfter)
+
+	l.take("api-key:reporting", 1, now.Add(3*time.Minute))
+	require.Len(t, l.buckets, 1, "idle buckets are forgotten")
+}
+
+func TestService_limitRate(t *testing.T) {
+	s := &Service{readLimits: newRateLimiter(2), writeLimits: newRateLimiter(1)}
+
+	handler := s.limitRate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	request := func(method string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(method, "/v1/people", nil)
+		handler.ServeHTTP(w, r.WithContext(actor.NewContext(r.Context(), "user:jdoe")))
+		return w
+	}
+
+	w := request("GET")
+	require.Equal(t, http.StatusNoContent, w.Code)
+	require.Equal(t, "2", w.Result().Header.Get("RateLimit-Limit"))
+	require.Equal(t, "1", w.Result().Header.Get("RateLimit-Remaining"))
+	require.Equal(t, "30", w.Result().Header.Get("RateLimit-Reset"))
+	require.Equal(t, "2;w=60", w.Result().Header.Get("RateLimit-Policy"))
+
+	require.Equal(t, http.StatusNoContent, request("POST").Code)
+
+	w = request("DELETE")
+	require.Equal(t, http.StatusTooManyRequests, w.Code)
+	require.Equal(t, "0", w.Result().Header.Get("RateLimit-Remaining"))
+	require.NotEmpty(t, w.Result().Header.Get("Retry-After"))
+
+	require.Equal(t, http.StatusNoContent, request("HEAD").Code, "separate budget for reading")
+
+	w = httptest.NewRecorder()
+	(&Service{}).limitRate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})).ServeHTTP(
+		w, httptest.NewRequest("GET", "/v1/people", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Empty(t, w.Result().Header.Get("RateLimit-Limit"), "unlimited")
+}
+
+func TestService_limitAddressRate(t *testing.T) {
+	s := &Service{addressLimits: newRateLimiter(1)}
+
+	handler := s.limitAddressRate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized) // e.g. invalid API key
+	}))
+
+	request := func(address string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/v1/people", nil)
+		r.RemoteAddr = address
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	require.Equal(t, http.StatusUnauthorized, request("192.0.2.1:41234").Code)
+	require.Equal(t, http.StatusTooManyRequests, request("192.0.2.1:41235").Code)
+	require.Equal(t, http.StatusUnauthorized, request("192.0.2.2:41234").Code, "separate budget")
+}
@@ -0,0 +1,40 @@
+package rest
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+)
+
+// https://barpav.github.io/demography-api/#/people/post_people__id__restore
+func (s *Service) restorePersonData(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 0)
+
+	if err != nil {
+		respondWithProblem(w, http.StatusNotFound)
+		return
+	}
+
+	var version int
+	version, err = s.storage.RestorePersonData(r.Context(), id)
+
+	if err != nil {
+		if _, ok := err.(ErrPersonDataNotFound); ok {
+			respondWithProblemDetail(w, http.StatusNotFound, "Deleted person data not found (not deleted or already purged).")
+			return
+		}
+
+		log.Err(err).Msg("Failed to restore person data.")
+		respondWithProblem(w, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("ETag", entityTag(version))
+
+	log.Info().Msg(fmt.Sprintf("Person data with id '%d' restored.", id))
+
+	w.WriteHeader(http.StatusNoContent)
+}
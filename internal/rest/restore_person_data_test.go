@@ -0,0 +1,110 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/barpav/demography/internal/rest/mocks"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_restorePersonData(t *testing.T) {
+	request := func(id string) *http.Request {
+		r := httptest.NewRequest("POST", "/v1/people/{id}:restore", nil)
+		ctx := chi.NewRouteContext()
+		ctx.URLParams.Add("id", id)
+		return r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, ctx))
+	}
+
+	type testService struct {
+		storage Storage
+	}
+	type args struct {
+		w *httptest.ResponseRecorder
+		r *http.Request
+	}
+	tests := []struct {
+		name        string
+		testService testService
+		args        args
+		wantHeaders map[string]string
+		wantStatus  int
+	}{
+		{
+			name: "Restored (204)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: request("101"),
+			},
+			testService: testService{
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("RestorePersonData", mock.Anything, int64(101)).Return(5, nil)
+					return s
+				}(),
+			},
+			wantHeaders: map[string]string{
+				"ETag": `"5"`,
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name: "Deleted person not found in DB (404)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: request("101"),
+			},
+			testService: testService{
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("RestorePersonData", mock.Anything, int64(101)).Return(0, ErrPersonDataNotFoundTest{})
+					return s
+				}(),
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name: "Person not found - bad id (404)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: request("some-text"),
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name: "Storage failure (500)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: request("101"),
+			},
+			testService: testService{
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("RestorePersonData", mock.Anything, int64(101)).Return(0, errors.New("test error"))
+					return s
+				}(),
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Service{
+				storage: tt.testService.storage,
+			}
+			s.restorePersonData(tt.args.w, tt.args.r)
+
+			require.Equal(t, tt.wantStatus, tt.args.w.Code)
+
+			for k, v := range tt.wantHeaders {
+				require.Equal(t, v, tt.args.w.Result().Header.Get(k))
+			}
+		})
+	}
+}
@@ -6,18 +6,26 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
+	"github.com/barpav/demography/internal/countries"
 	"github.com/barpav/demography/internal/rest/models"
+	"github.com/barpav/demography/internal/rest/negotiation"
 	"github.com/rs/zerolog/log"
 )
 
 // https://barpav.github.io/demography-api/#/people/get_people
 func (s *Service) searchByData(w http.ResponseWriter, r *http.Request) {
-	switch r.Header.Get("Accept") {
-	case "", models.MimeTypeSearchResultV1:
+	switch format := negotiation.Accept(r.Header.Get("Accept"),
+		models.MimeTypeSearchResultV1, models.MimeTypeSearchResultV2, models.MimeTypeCSV, models.MimeTypeNDJSON); format {
+	case models.MimeTypeSearchResultV1:
 		s.searchByDataV1(w, r)
+	case models.MimeTypeSearchResultV2:
+		s.searchByDataV2(w, r)
+	case models.MimeTypeCSV, models.MimeTypeNDJSON:
+		s.exportSearchResultV1(w, r, format)
 	default:
-		w.WriteHeader(http.StatusNotAcceptable)
+		respondWithProblem(w, http.StatusNotAcceptable)
 		return
 	}
 }
@@ -25,8 +33,17 @@ func (s *Service) searchByData(w http.ResponseWriter, r *http.Request) {
 func (s *Service) searchByDataV1(w http.ResponseWriter, r *http.Request) {
 	filters, err := searchFilters(r)
 
+	var expand bool
+	if err == nil {
+		expand, err = expandCountry(r)
+	}
+
 	if err != nil {
-		http.Error(w, err.Error(), 400)
+		respondWithInvalidRequest(w, err)
+		return
+	}
+
+	if !s.withinHistory(w, r, filters.AsOf) {
 		return
 	}
 
@@ -35,10 +52,16 @@ func (s *Service) searchByDataV1(w http.ResponseWriter, r *http.Request) {
 
 	if err != nil {
 		log.Err(err).Msg("Failed to receive search result (v1).")
-		w.WriteHeader(http.StatusInternalServerError)
+		respondWithProblem(w, http.StatusInternalServerError)
 		return
 	}
 
+	if expand {
+		for _, data := range result.Data {
+			data.CountryDetails = models.NewCountryV1(data.Country)
+		}
+	}
+
 	w.Header().Set("Content-Type", models.MimeTypeSearchResultV1)
 	err = json.NewEncoder(w).Encode(result)
 
@@ -51,14 +74,92 @@ func (s *Service) searchByDataV1(w http.ResponseWriter, r *http.Request) {
 	log.Info().Msg(fmt.Sprintf("Search results: %d", result.Total))
 }
 
+func (s *Service) searchByDataV2(w http.ResponseWriter, r *http.Request) {
+	filters, err := searchFilters(r)
+
+	var expand bool
+	if err == nil {
+		expand, err = expandCountry(r)
+	}
+
+	if err != nil {
+		respondWithInvalidRequest(w, err)
+		return
+	}
+
+	if !s.withinHistory(w, r, filters.AsOf) {
+		return
+	}
+
+	var result *models.SearchResultV2
+	result, err = s.storage.SearchResultV2(r.Context(), filters)
+
+	if err != nil {
+		log.Err(err).Msg("Failed to receive search result (v2).")
+		respondWithProblem(w, http.StatusInternalServerError)
+		return
+	}
+
+	if expand {
+		for _, data := range result.Data {
+			data.CountryDetails = models.NewCountryV1(data.Country)
+		}
+	}
+
+	w.Header().Set("Content-Type", models.MimeTypeSearchResultV2)
+	err = json.NewEncoder(w).Encode(result)
+
+	if err != nil {
+		log.Err(err).Msg("Failed to serialize search result (v2).")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	log.Info().Msg(fmt.Sprintf("Search results (v2): %d", result.Total))
+}
+
 func searchFilters(r *http.Request) (filters *models.SearchFilters, err error) {
+	filters = &models.SearchFilters{}
+	err = readPersonFilters(r, filters)
+
+	var parseErr error
+	var param int64
+	param, parseErr = integerQueryParameter(r, "after")
+
+	if parseErr != nil {
+		err = errors.Join(err, parseErr)
+	} else {
+		filters.After = param
+	}
+
+	const limitDefault, limitMin, limitMax = 30, 1, 100
+	param, parseErr = boundedIntegerQueryParameter(r, "limit", limitDefault, limitMin, limitMax)
+
+	if parseErr != nil {
+		err = errors.Join(err, parseErr)
+	} else {
+		filters.Limit = int(param)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return filters, nil
+}
+
+// Filters by person data, common for search and analytics operations.
+func readPersonFilters(r *http.Request, filters *models.SearchFilters) (err error) {
 	query := r.URL.Query()
-	filters = &models.SearchFilters{
-		Surname:    query.Get("surname"),
-		Name:       query.Get("name"),
-		Patronymic: query.Get("patronymic"),
-		Gender:     query.Get("gender"),
-		Country:    query.Get("country"),
+	filters.Surname = query.Get("surname")
+	filters.Name = query.Get("name")
+	filters.Patronymic = query.Get("patronymic")
+	filters.Gender = query.Get("gender")
+	filters.Country = query.Get("country")
+
+	// unknown codes are kept as is to find incorrect data
+	if country, ok := countries.Normalize(filters.Country); ok {
+		filters.Country = country
 	}
 
 	var parseErr error
@@ -71,40 +172,22 @@ func searchFilters(r *http.Request) (filters *models.SearchFilters, err error) {
 		filters.Age = int(param)
 	}
 
-	param, parseErr = integerQueryParameter(r, "after")
+	filters.Translit, parseErr = booleanQueryParameter(r, "translit")
 
 	if parseErr != nil {
 		err = errors.Join(err, parseErr)
-	} else {
-		filters.After = param
 	}
 
-	const limitDefault = 30
+	filters.AsOf, parseErr = timeQueryParameter(r, "as_of")
 
-	if query.Get("limit") == "" {
-		filters.Limit = limitDefault
-	} else {
-		const limitMin = 1
-		const limitMax = 100
-
-		param, parseErr = integerQueryParameter(r, "limit")
-
-		if parseErr != nil {
-			err = errors.Join(err, parseErr)
-		} else {
-			if param < limitMin || param > limitMax {
-				err = errors.Join(err, fmt.Errorf("Invalid parameter 'limit': min %d, max %d.", limitMin, limitMax))
-			} else {
-				filters.Limit = int(param)
-			}
-		}
+	if parseErr != nil {
+		err = errors.Join(err, parseErr)
 	}
 
-	if err != nil {
-		return nil, err
-	}
+	// like deleted people list, past data of people deleted since then requires deletion scope
+	filters.WithDeleted = !filters.AsOf.IsZero() && allowed(r, ScopePeopleDelete)
 
-	return filters, nil
+	return err
 }
 
 func integerQueryParameter(r *http.Request, name string) (value int64, err error) {
@@ -122,3 +205,59 @@ func integerQueryParameter(r *http.Request, name string) (value int64, err error
 
 	return value, nil
 }
+
+// Returns default value if parameter is not specified.
+func boundedIntegerQueryParameter(r *http.Request, name string, defaultValue, min, max int64) (value int64, err error) {
+	if r.URL.Query().Get(name) == "" {
+		return defaultValue, nil
+	}
+
+	value, err = integerQueryParameter(r, name)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if value < min || value > max {
+		return 0, fmt.Errorf("Invalid parameter '%s': min %d, max %d.", name, min, max)
+	}
+
+	return value, nil
+}
+
+// Timestamp (RFC 3339) or date (start of the day in UTC). Returns zero time if parameter is not specified.
+func timeQueryParameter(r *http.Request, name string) (value time.Time, err error) {
+	param := r.URL.Query().Get(name)
+
+	if param == "" {
+		return time.Time{}, nil
+	}
+
+	value, err = time.Parse(time.RFC3339Nano, param)
+
+	if err != nil {
+		value, err = time.Parse(models.DateFormat, param)
+	}
+
+	if err != nil {
+		return time.Time{}, fmt.Errorf("Parameter '%s' must be a timestamp (RFC 3339) or a date (YYYY-MM-DD).", name)
+	}
+
+	return value, nil
+}
+
+func booleanQueryParameter(r *http.Request, name string) (value bool, err error) {
+	param := r.URL.Query().Get(name)
+
+	if param == "" {
+		return false, nil
+	}
+
+	value, err = strconv.ParseBool(param)
+
+	if err != nil {
+		return false, fmt.Errorf("Parameter '%s' must be a boolean type.", name)
+	}
+
+	return value, nil
+}
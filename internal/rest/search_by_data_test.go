@@ -1,11 +1,14 @@
 package rest
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/barpav/demography/internal/rest/mocks"
 	"github.com/barpav/demography/internal/rest/models"
@@ -27,6 +30,7 @@ func TestService_searchByData(t *testing.T) {
 		args        args
 		wantHeaders map[string]string
 		wantBody    *models.SearchResultV1
+		wantExport  string
 		wantStatus  int
 	}{
 		{
@@ -85,6 +89,125 @@ func TestService_searchByData(t *testing.T) {
 			},
 			wantStatus: http.StatusOK,
 		},
+		{
+			name: "Success at the point in time (200)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					r := httptest.NewRequest("GET", "/v1/people?country=RU&as_of=2023-09-01T15:00:00%2B03:00", nil)
+					r.Header.Set("Accept", models.MimeTypeSearchResultV1)
+					return r
+				}(),
+			},
+			testService: testService{
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("HistoryStart", mock.Anything).Return(time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC), nil)
+					s.On("SearchResultV1", mock.Anything, mock.MatchedBy(func(f *models.SearchFilters) bool {
+						return f.Country == "RU" && f.Limit == 30 && f.AsOf.Equal(time.Date(2023, 9, 1, 12, 0, 0, 0, time.UTC))
+					})).Return(&models.SearchResultV1{}, nil)
+					return s
+				}(),
+			},
+			wantHeaders: map[string]string{
+				"Content-Type": models.MimeTypeSearchResultV1,
+			},
+			wantBody:   &models.SearchResultV1{},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "Point in time before start of history (400)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					r := httptest.NewRequest("GET", "/v1/people?as_of=2018-12-31", nil)
+					r.Header.Set("Accept", models.MimeTypeSearchResultV1)
+					return r
+				}(),
+			},
+			testService: testService{
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("HistoryStart", mock.Anything).Return(time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC), nil)
+					return s
+				}(),
+			},
+			wantHeaders: map[string]string{},
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			name: "Success at the point in time including deleted people (200)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					r := httptest.NewRequest("GET", "/v1/people?as_of=2023-09-01", nil)
+					r.Header.Set("Accept", models.MimeTypeSearchResultV1)
+					return r.WithContext(context.WithValue(r.Context(), principalKey{}, &principal{scopes: []string{ScopePeopleDelete}}))
+				}(),
+			},
+			testService: testService{
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("HistoryStart", mock.Anything).Return(time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC), nil)
+					s.On("SearchResultV1", mock.Anything, &models.SearchFilters{
+						Limit:       30,
+						AsOf:        time.Date(2023, 9, 1, 0, 0, 0, 0, time.UTC),
+						WithDeleted: true,
+					}).Return(&models.SearchResultV1{}, nil)
+					return s
+				}(),
+			},
+			wantHeaders: map[string]string{
+				"Content-Type": models.MimeTypeSearchResultV1,
+			},
+			wantBody:   &models.SearchResultV1{},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "Success with transliteration (200)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					r := httptest.NewRequest("GET", "/v1/people?surname=%D0%98%D0%B2%D0%B0%D0%BD%D0%BE%D0%B2&translit=true", nil)
+					r.Header.Set("Accept", models.MimeTypeSearchResultV1)
+					return r
+				}(),
+			},
+			testService: testService{
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("SearchResultV1", mock.Anything, &models.SearchFilters{
+						Surname:  "Иванов",
+						Limit:    30,
+						Translit: true,
+					}).Return(&models.SearchResultV1{
+						Total: 1,
+						Data: []*models.EnrichedPersonDataV1{
+							{
+								Id:      5,
+								Surname: "Ivanov",
+								Name:    "Ivan",
+							},
+						},
+					}, nil)
+					return s
+				}(),
+			},
+			wantHeaders: map[string]string{
+				"Content-Type": models.MimeTypeSearchResultV1,
+			},
+			wantBody: &models.SearchResultV1{
+				Total: 1,
+				Data: []*models.EnrichedPersonDataV1{
+					{
+						Id:      5,
+						Surname: "Ivanov",
+						Name:    "Ivan",
+					},
+				},
+			},
+			wantStatus: http.StatusOK,
+		},
 		{
 			name: "Success without filters (200)",
 			args: args{
@@ -139,12 +262,122 @@ func TestService_searchByData(t *testing.T) {
 			},
 			wantStatus: http.StatusOK,
 		},
+		{
+			name: "Success (v2) with age derived from birth date (200)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					r := httptest.NewRequest("GET", "/v1/people?age=33", nil)
+					r.Header.Set("Accept", models.MimeTypeSearchResultV2)
+					return r
+				}(),
+			},
+			testService: testService{
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("SearchResultV2", mock.Anything, &models.SearchFilters{Age: 33, Limit: 30}).Return(
+						&models.SearchResultV2{
+							Total: 1,
+							Data: []*models.EnrichedPersonDataV2{
+								{Id: 5, Surname: "Ivanov", Name: "Ivan", BirthDate: "1990-05-01", Age: 33, Gender: "male"},
+							},
+						}, nil)
+					return s
+				}(),
+			},
+			wantHeaders: map[string]string{
+				"Content-Type": models.MimeTypeSearchResultV2,
+			},
+			wantExport: `{"total":1,"data":[{"id":5,"surname":"Ivanov","name":"Ivan","birthDate":"1990-05-01","age":33,"gender":"male"}]}` + "\n",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "Export as CSV (200)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					r := httptest.NewRequest("GET", "/v1/people?name=Ivan&limit=1", nil)
+					r.Header.Set("Accept", models.MimeTypeCSV)
+					return r
+				}(),
+			},
+			testService: testService{
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("ExportSearchResultV1", mock.Anything, &models.SearchFilters{Name: "Ivan"}, mock.Anything).
+						Run(func(args mock.Arguments) {
+							receive := args.Get(2).(func(*models.EnrichedPersonDataV1) error)
+							receive(&models.EnrichedPersonDataV1{Id: 5, Surname: "Ivanov", Name: "Ivan", Age: 50})
+							receive(&models.EnrichedPersonDataV1{Id: 10, Surname: "Petrov", Name: "Ivan", Country: "RU"})
+						}).Return(nil)
+					return s
+				}(),
+			},
+			wantHeaders: map[string]string{
+				"Content-Type":        models.MimeTypeCSV,
+				"Content-Disposition": `attachment; filename="people.csv"`,
+			},
+			wantExport: "id,surname,name,patronymic,age,gender,country\n" +
+				"5,Ivanov,Ivan,,50,,\n" +
+				"10,Petrov,Ivan,,,,RU\n",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "Export as NDJSON (200)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					r := httptest.NewRequest("GET", "/v1/people?name=Ivan", nil)
+					r.Header.Set("Accept", models.MimeTypeNDJSON)
+					return r
+				}(),
+			},
+			testService: testService{
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("ExportSearchResultV1", mock.Anything, &models.SearchFilters{Name: "Ivan"}, mock.Anything).
+						Run(func(args mock.Arguments) {
+							receive := args.Get(2).(func(*models.EnrichedPersonDataV1) error)
+							receive(&models.EnrichedPersonDataV1{Id: 5, Surname: "Ivanov", Name: "Ivan", Age: 50})
+							receive(&models.EnrichedPersonDataV1{Id: 10, Surname: "Petrov", Name: "Ivan", Country: "RU"})
+						}).Return(nil)
+					return s
+				}(),
+			},
+			wantHeaders: map[string]string{
+				"Content-Type":        models.MimeTypeNDJSON,
+				"Content-Disposition": `attachment; filename="people.ndjson"`,
+			},
+			wantExport: `{"id":5,"surname":"Ivanov","name":"Ivan","age":50}` + "\n" +
+				`{"id":10,"surname":"Petrov","name":"Ivan","country":"RU"}` + "\n",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "Export failed (500)",
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					r := httptest.NewRequest("GET", "/v1/people", nil)
+					r.Header.Set("Accept", models.MimeTypeCSV)
+					return r
+				}(),
+			},
+			testService: testService{
+				storage: func() *mocks.Storage {
+					s := mocks.NewStorage(t)
+					s.On("ExportSearchResultV1", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("test"))
+					return s
+				}(),
+			},
+			wantHeaders: map[string]string{},
+			wantStatus:  http.StatusInternalServerError,
+		},
 		{
 			name: "Incorrect parameters (400)",
 			args: args{
 				w: httptest.NewRecorder(),
 				r: func() *http.Request {
-					r := httptest.NewRequest("GET", "/v1/people?age=old&limit=1000", nil)
+					r := httptest.NewRequest("GET", "/v1/people?age=old&limit=1000&translit=maybe", nil)
 					r.Header.Set("Accept", models.MimeTypeSearchResultV1)
 					return r
 				}(),
@@ -190,6 +423,11 @@ func TestService_searchByData(t *testing.T) {
 
 			require.Equal(t, tt.wantStatus, tt.args.w.Code)
 
+			if tt.wantExport != "" {
+				require.Equal(t, tt.wantExport, tt.args.w.Body.String())
+				return
+			}
+
 			if tt.wantBody == nil {
 				return
 			}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/barpav/demography/internal/rest/models"
 	"github.com/go-chi/chi/v5"
@@ -16,6 +17,10 @@ type Service struct {
 	server   *http.Server
 	stats    StatisticsProvider
 	storage  Storage
+
+	readLimits, writeLimits *rateLimiter // nil if unlimited
+	addressLimits           *rateLimiter // before authentication
+	recordLimits            *rateLimiter // batches and imports
 }
 
 //go:generate mockery --name StatisticsProvider
@@ -28,17 +33,49 @@ type StatisticsProvider interface {
 //go:generate mockery --name Storage
 type Storage interface {
 	CreateNewPersonDataV1(ctx context.Context, data *models.EnrichedPersonDataV1) error
+	CreateNewPersonDataV2(ctx context.Context, data *models.EnrichedPersonDataV2) error
+	CreateNewPeopleDataV1(ctx context.Context, data []*models.EnrichedPersonDataV1) error
+	ImportPeopleDataV1(ctx context.Context, data []*models.EnrichedPersonDataV1) error
 	SearchResultV1(ctx context.Context, filters *models.SearchFilters) (result *models.SearchResultV1, err error)
+	SearchResultV2(ctx context.Context, filters *models.SearchFilters) (result *models.SearchResultV2, err error)
+	ExportSearchResultV1(ctx context.Context, filters *models.SearchFilters, receive func(*models.EnrichedPersonDataV1) error) error
 	EnrichedPersonDataV1(ctx context.Context, id int64) (*models.EnrichedPersonDataV1, error)
-	UpdatePersonDataV1(ctx context.Context, id int64, data *models.EditedPersonDataV1) error
-	DeletePersonData(ctx context.Context, id int64) error
+	EnrichedPersonDataV2(ctx context.Context, id int64) (*models.EnrichedPersonDataV2, error)
+	EnrichedPersonDataV1AsOf(ctx context.Context, id int64, asOf time.Time) (*models.EnrichedPersonDataV1, error)
+	EnrichedPersonDataV2AsOf(ctx context.Context, id int64, asOf time.Time) (*models.EnrichedPersonDataV2, error)
+	HistoryStart(ctx context.Context) (time.Time, error)
+	UpdatePersonDataV1(ctx context.Context, id int64, data *models.EditedPersonDataV1, versions []int) (int, error)
+	UpdatePersonDataV2(ctx context.Context, id int64, data *models.EditedPersonDataV2, versions []int) (int, error)
+	PatchPersonDataV1(ctx context.Context, id int64, patch *models.PersonDataPatchV1, versions []int) (int, error)
+	DeletePersonData(ctx context.Context, id int64, versions []int) error
+	RestorePersonData(ctx context.Context, id int64) (int, error)
+	DeletedPeopleV1(ctx context.Context, after int64, limit int) (*models.DeletedPeopleV1, error)
+	PersonDataHistoryV1(ctx context.Context, id, after int64, limit int) (*models.PersonDataHistoryV1, error)
+	PersonDataExists(ctx context.Context, id int64) (bool, error)
+	CountPeople(ctx context.Context, filters *models.SearchFilters) (int64, error)
+	PatchPeopleDataV1(ctx context.Context, filters *models.SearchFilters, patch *models.PersonDataPatchV1, expected int64) (int64, error)
+	DeletePeople(ctx context.Context, filters *models.SearchFilters, expected int64) (int64, error)
+	DemographicsV1(ctx context.Context, filters *models.SearchFilters, grouping *models.DemographicsGrouping) (*models.DemographicsV1, error)
+	AgeHistogramV1(ctx context.Context, filters *models.SearchFilters, width int) (*models.AgeHistogramV1, error)
+	NamePopularityV1(ctx context.Context, field models.NameField, filters *models.SearchFilters, limit int) (*models.NamePopularityV1, error)
+	NameSummaryV1(ctx context.Context, filters *models.SearchFilters) (*models.NameSummaryV1, error)
+	ReserveIdempotencyKey(ctx context.Context, client, key string, reservation, fingerprint []byte, lock, ttl time.Duration) (*models.IdempotentResponse, error)
+	SaveIdempotentResponse(ctx context.Context, client, key string, reservation []byte, response *models.IdempotentResponse) error
+	ReleaseIdempotencyKey(ctx context.Context, client, key string, reservation []byte) error
+	APIKey(ctx context.Context, keyHash []byte) (*models.APIKey, error)
 }
 
-func (s *Service) Start(storage Storage, stats StatisticsProvider) {
+func (s *Service) Start(storage Storage, stats StatisticsProvider) error {
 	s.cfg = &config{}
-	s.cfg.Read()
+	err := s.cfg.Read()
+
+	if err != nil {
+		return fmt.Errorf("failed to read HTTP service settings: %w", err)
+	}
 
 	s.storage, s.stats = storage, stats
+	s.readLimits, s.writeLimits = newRateLimiter(s.cfg.readsPerMinute), newRateLimiter(s.cfg.writesPerMinute)
+	s.addressLimits, s.recordLimits = newRateLimiter(s.cfg.addressRequestsPerMinute), newRateLimiter(s.cfg.recordsPerMinute)
 
 	s.server = &http.Server{
 		Addr:    fmt.Sprintf(":%s", s.cfg.port),
@@ -56,9 +93,15 @@ func (s *Service) Start(storage Storage, stats StatisticsProvider) {
 
 		s.shutdown <- struct{}{}
 	}()
+
+	return nil
 }
 
 func (s *Service) Stop(ctx context.Context) (err error) {
+	if s.server == nil {
+		return nil // not started
+	}
+
 	err = s.server.Shutdown(ctx)
 
 	if err != nil {
@@ -76,13 +119,36 @@ func (s *Service) Shutdown() <-chan struct{} {
 func (s *Service) operations() *chi.Mux {
 	ops := chi.NewRouter()
 
-	ops.Use(s.enableCORS)
-
-	ops.Post("/v1/people", s.addNewPerson)
-	ops.Get("/v1/people", s.searchByData)
-	ops.Get("/v1/people/{id}", s.getPersonData)
-	ops.Put("/v1/people/{id}", s.editPersonData)
-	ops.Delete("/v1/people/{id}", s.deletePersonData)
+	ops.Use(s.logRequest)
+	ops.Use(s.cfg.cors.handler)
+	ops.Use(s.limitAddressRate)
+	ops.Use(s.identifyActor)
+	ops.Use(s.authenticate)
+	ops.Use(s.limitRate)
+
+	read, write, remove := s.authorize(ScopePeopleRead), s.authorize(ScopePeopleWrite), s.authorize(ScopePeopleDelete)
+
+	ops.With(write, s.idempotent).Post("/v1/people", s.addNewPerson)
+	ops.With(write).Post("/v1/people:import", s.importPeople)
+	ops.With(write).Post("/v1/people:batch", s.addNewPeopleBatch)
+	ops.With(read).Get("/v1/people", s.searchByData)
+	ops.With(write).Patch("/v1/people", s.patchPeople)
+	ops.With(remove).Delete("/v1/people", s.deletePeople)
+	ops.With(remove).Get("/v1/people:deleted", s.getDeletedPeople)
+	ops.With(read).Get("/v1/people/{id}", s.getPersonData)
+	ops.With(write).Put("/v1/people/{id}", s.editPersonData)
+	ops.With(write).Patch("/v1/people/{id}", s.patchPersonData)
+	ops.With(remove).Delete("/v1/people/{id}", s.deletePersonData)
+	ops.With(remove).Post("/v1/people/{id}:restore", s.restorePersonData)
+	ops.With(read).Get("/v1/people/{id}/history", s.getPersonDataHistory)
+
+	ops.With(read).Get("/v1/demographics", s.getDemographics)
+	ops.With(read).Get("/v1/demographics/age-histogram", s.getAgeHistogram)
+
+	ops.With(read).Get("/v1/names", s.getNamePopularity(models.NameFieldName))
+	ops.With(read).Get("/v1/names/{name}", s.getNameSummary)
+	ops.With(read).Get("/v1/surnames", s.getNamePopularity(models.NameFieldSurname))
+	ops.With(read).Get("/v1/patronymics", s.getNamePopularity(models.NameFieldPatronymic))
 
 	return ops
 }
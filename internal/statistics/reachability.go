@@ -0,0 +1,33 @@
+package statistics
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// All statistics services respond (regardless of quotas and data).
+func (p *Provider) Reachable(ctx context.Context) error {
+	for _, url := range []string{ageStatsURL, genderStatsURL, countryStatsURL} {
+		r, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+
+		if err == nil {
+			var resp *http.Response
+			resp, err = http.DefaultClient.Do(r)
+
+			if err == nil {
+				resp.Body.Close()
+
+				if resp.StatusCode >= http.StatusInternalServerError {
+					err = fmt.Errorf("status %d", resp.StatusCode)
+				}
+			}
+		}
+
+		if err != nil {
+			return fmt.Errorf("statistics service %s is unreachable: %w", url, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package translit
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Must be kept in sync with translit_ru() SQL function (see migrations).
+var cyrillicToLatin = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e", 'ж': "zh",
+	'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+	'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts",
+	'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu",
+	'я': "ya", 'і': "i", 'ї': "yi", 'є': "ye", 'ґ': "g", 'ў': "u",
+}
+
+// ToLatin transliterates Cyrillic letters into Latin preserving letter case,
+// e.g. "Иванов" -> "Ivanov", "ЩУКИН" -> "SHCHUKIN". Other characters are kept as is.
+func ToLatin(value string) string {
+	var b strings.Builder
+	b.Grow(len(value))
+
+	for i, r := range value {
+		latin, ok := cyrillicToLatin[unicode.ToLower(r)]
+
+		if !ok {
+			b.WriteRune(r)
+			continue
+		}
+
+		if unicode.IsUpper(r) && latin != "" {
+			next, _ := utf8.DecodeRuneInString(value[i+utf8.RuneLen(r):])
+
+			if unicode.IsUpper(next) {
+				latin = strings.ToUpper(latin)
+			} else {
+				latin = strings.ToUpper(latin[:1]) + latin[1:]
+			}
+		}
+
+		b.WriteString(latin)
+	}
+
+	return b.String()
+}
+
+// Normalize returns script and case independent form of the value,
+// so "Иванов", "IVANOV" and "Ivanov" are all normalized to "ivanov".
+func Normalize(value string) string {
+	return ToLatin(strings.ToLower(value))
+}
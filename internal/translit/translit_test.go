@@ -0,0 +1,109 @@
+package translit
+
+import (
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestToLatin(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{value: "Иванов", want: "Ivanov"},
+		{value: "ИВАНОВ", want: "IVANOV"},
+		{value: "Щукин", want: "Shchukin"},
+		{value: "ЩУКИН", want: "SHCHUKIN"},
+		{value: "Жанна", want: "Zhanna"},
+		{value: "Хрущёв", want: "Khrushchev"},
+		{value: "Подъячев", want: "Podyachev"},
+		{value: "Ольга", want: "Olga"},
+		{value: "Юлия-Мария", want: "Yuliya-Mariya"},
+		{value: "Ющенко", want: "Yushchenko"},
+		{value: "Їжак", want: "Yizhak"},
+		{value: "Ivanov", want: "Ivanov"},
+		{value: "Иван Ivan", want: "Ivan Ivan"},
+		{value: "Ь", want: ""},
+		{value: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			require.Equal(t, tt.want, ToLatin(tt.value))
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	for _, value := range []string{"Иванов", "ИВАНОВ", "Ivanov", "IVANOV", "иванов"} {
+		require.Equal(t, "ivanov", Normalize(value), value)
+	}
+
+	require.Equal(t, "shchukin", Normalize("ЩУКИН"))
+}
+
+// Normalize must produce the same values as translit_ru() SQL function,
+// otherwise searches by normalized names miss people.
+func TestNormalize_sqlParity(t *testing.T) {
+	translitRu := sqlTranslitRu(t)
+
+	values := []string{"Иванов", "ЩУКИН", "Хрущёв", "Подъячев", "Юлия", "Їжак", "Єва", "Ґалаґан", "Ўладзімір", "Ivanov"}
+
+	for letter := range cyrillicToLatin {
+		values = append(values, string(letter))
+	}
+
+	for _, value := range values {
+		require.Equal(t, translitRu(value), Normalize(value), value)
+	}
+}
+
+// Emulates translit_ru() defined in migrations: lower(), chain of replace() and translate().
+func sqlTranslitRu(t *testing.T) func(string) string {
+	migration, err := os.ReadFile("../../migrations/000003_add_normalized_names.up.sql")
+	require.NoError(t, err)
+
+	body := string(migration)
+	body = body[strings.Index(body, "SELECT translate("):strings.LastIndex(body, "$$;")]
+
+	var literals []string
+
+	for _, match := range regexp.MustCompile(`'([^']*)'`).FindAllStringSubmatch(body, -1) {
+		literals = append(literals, match[1])
+	}
+
+	require.True(t, len(literals) >= 2 && len(literals)%2 == 0, "unexpected translit_ru() definition")
+
+	replacements := literals[:len(literals)-2]
+	from, to := []rune(literals[len(literals)-2]), []rune(literals[len(literals)-1])
+
+	return func(value string) string {
+		value = strings.ToLower(value)
+
+		for i := 0; i < len(replacements); i += 2 {
+			value = strings.ReplaceAll(value, replacements[i], replacements[i+1])
+		}
+
+		var b strings.Builder
+
+		for _, r := range value {
+			i := strings.IndexRune(string(from), r)
+
+			switch {
+			case i < 0:
+				b.WriteRune(r)
+			case utf8.RuneCountInString(string(from)[:i]) < len(to):
+				b.WriteRune(to[utf8.RuneCountInString(string(from)[:i])])
+			default:
+				// characters without replacement are removed by translate()
+			}
+		}
+
+		return b.String()
+	}
+}
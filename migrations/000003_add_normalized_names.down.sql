@@ -0,0 +1,6 @@
+ALTER TABLE people
+    DROP COLUMN surname_normalized,
+    DROP COLUMN person_name_normalized,
+    DROP COLUMN patronymic_normalized;
+
+DROP FUNCTION translit_ru(text);
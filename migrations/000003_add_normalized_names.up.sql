@@ -0,0 +1,22 @@
+-- Must be kept in sync with internal/translit package.
+CREATE FUNCTION translit_ru(value text) RETURNS text
+LANGUAGE sql IMMUTABLE STRICT PARALLEL SAFE
+AS $$
+    SELECT translate(
+        replace(replace(replace(replace(replace(replace(replace(replace(replace(replace(
+            lower(value),
+            'щ', 'shch'), 'ж', 'zh'), 'х', 'kh'), 'ц', 'ts'), 'ч', 'ch'),
+            'ш', 'sh'), 'ю', 'yu'), 'я', 'ya'), 'ї', 'yi'), 'є', 'ye'),
+        'абвгдеёзийклмнопрстуфыэіґўъь',
+        'abvgdeeziyklmnoprstufyeigu'
+    );
+$$;
+
+ALTER TABLE people
+    ADD COLUMN surname_normalized text GENERATED ALWAYS AS (translit_ru(surname)) STORED,
+    ADD COLUMN person_name_normalized text GENERATED ALWAYS AS (translit_ru(person_name)) STORED,
+    ADD COLUMN patronymic_normalized text GENERATED ALWAYS AS (translit_ru(patronymic)) STORED;
+
+CREATE INDEX people_surname_normalized_idx ON people (surname_normalized);
+CREATE INDEX people_person_name_normalized_idx ON people (person_name_normalized);
+CREATE INDEX people_patronymic_normalized_idx ON people (patronymic_normalized);
@@ -0,0 +1,4 @@
+DROP TRIGGER people_version ON people;
+DROP FUNCTION increment_people_version();
+
+ALTER TABLE people DROP COLUMN version;
@@ -0,0 +1,16 @@
+ALTER TABLE people ADD COLUMN version integer NOT NULL DEFAULT 1;
+
+-- Every change of person data produces a new version (optimistic concurrency control).
+CREATE FUNCTION increment_people_version() RETURNS trigger
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    NEW.version := OLD.version + 1;
+    RETURN NEW;
+END;
+$$;
+
+CREATE TRIGGER people_version
+    BEFORE UPDATE ON people
+    FOR EACH ROW
+    EXECUTE FUNCTION increment_people_version();
@@ -0,0 +1,10 @@
+CREATE TABLE idempotency_keys (
+    idempotency_key varchar(255) PRIMARY KEY,
+    fingerprint bytea NOT NULL,
+    status_code smallint, -- NULL while original request is in progress
+    headers jsonb,
+    body bytea,
+    expires_at timestamptz NOT NULL
+);
+
+CREATE INDEX idempotency_keys_expires_at_idx ON idempotency_keys (expires_at);
@@ -0,0 +1,5 @@
+ALTER TABLE people
+    DROP COLUMN birth_date,
+    DROP COLUMN middle_names,
+    DROP COLUMN nationalities,
+    DROP COLUMN tags;
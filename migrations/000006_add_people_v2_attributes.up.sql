@@ -0,0 +1,7 @@
+ALTER TABLE people
+    ADD COLUMN birth_date date,
+    ADD COLUMN middle_names varchar(150)[] NOT NULL DEFAULT '{}',
+    ADD COLUMN nationalities varchar(5)[] NOT NULL DEFAULT '{}',
+    ADD COLUMN tags varchar(50)[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX people_tags_idx ON people USING gin (tags);
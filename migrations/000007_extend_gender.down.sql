@@ -0,0 +1,12 @@
+ALTER TABLE people DROP COLUMN gender_source;
+DROP TYPE gender_source;
+
+-- enum values can't be removed, so the type is recreated
+ALTER TABLE people DISABLE TRIGGER people_version;
+UPDATE people SET gender = NULL WHERE gender IN ('other', 'unknown');
+ALTER TABLE people ENABLE TRIGGER people_version;
+
+ALTER TYPE gender RENAME TO gender_extended;
+CREATE TYPE gender AS ENUM ('male', 'female');
+ALTER TABLE people ALTER COLUMN gender TYPE gender USING gender::text::gender;
+DROP TYPE gender_extended;
@@ -0,0 +1,11 @@
+ALTER TYPE gender ADD VALUE 'other';
+ALTER TYPE gender ADD VALUE 'unknown'; -- not determined by statistics or not disclosed by the person
+
+CREATE TYPE gender_source AS ENUM ('statistics', 'declared');
+
+ALTER TABLE people ADD COLUMN gender_source gender_source;
+
+-- not a change of person data, version is kept
+ALTER TABLE people DISABLE TRIGGER people_version;
+UPDATE people SET gender_source = 'statistics' WHERE gender IS NOT NULL;
+ALTER TABLE people ENABLE TRIGGER people_version;
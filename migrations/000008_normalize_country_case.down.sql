@@ -0,0 +1 @@
+-- irreversible normalization, nothing to do
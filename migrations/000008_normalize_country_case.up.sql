@@ -0,0 +1,4 @@
+-- country codes are validated against ISO 3166-1 alpha-2 registry in upper case since then
+ALTER TABLE people DISABLE TRIGGER people_version;
+UPDATE people SET country = upper(trim(country)) WHERE country <> upper(trim(country));
+ALTER TABLE people ENABLE TRIGGER people_version;
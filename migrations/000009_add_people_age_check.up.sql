@@ -0,0 +1,6 @@
+-- unrealistic ages (if any) are considered unknown, version is kept
+ALTER TABLE people DISABLE TRIGGER people_version;
+UPDATE people SET age = NULL WHERE age < 0 OR age > 150;
+ALTER TABLE people ENABLE TRIGGER people_version;
+
+ALTER TABLE people ADD CONSTRAINT people_age_check CHECK (age BETWEEN 0 AND 150);
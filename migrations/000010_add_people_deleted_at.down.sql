@@ -0,0 +1,3 @@
+DELETE FROM people WHERE deleted_at IS NOT NULL;
+
+ALTER TABLE people DROP COLUMN deleted_at;
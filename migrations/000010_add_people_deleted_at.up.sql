@@ -0,0 +1,4 @@
+-- Soft deletion: deleted person data is kept for restoration until purged after retention period.
+ALTER TABLE people ADD COLUMN deleted_at timestamptz;
+
+CREATE INDEX people_deleted_at_idx ON people (deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,4 @@
+DROP TRIGGER people_history ON people;
+DROP FUNCTION record_people_history();
+DROP TABLE people_history;
+DROP TYPE people_change;
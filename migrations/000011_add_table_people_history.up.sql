@@ -0,0 +1,49 @@
+CREATE TYPE people_change AS ENUM ('create', 'update', 'delete', 'restore', 'purge');
+
+-- Every change of person data with full snapshots of the row before and after the change.
+CREATE TABLE people_history (
+    id bigserial PRIMARY KEY,
+    person_id bigint NOT NULL, -- kept after person data is purged
+    change people_change NOT NULL,
+    version integer NOT NULL, -- of person data after change (before purge)
+    actor varchar(255), -- set via 'demography.actor' setting of transaction
+    changed_at timestamptz NOT NULL DEFAULT now(),
+    before jsonb,
+    after jsonb
+);
+
+CREATE INDEX people_history_person_id_idx ON people_history (person_id, id);
+
+CREATE FUNCTION record_people_history() RETURNS trigger
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        INSERT INTO people_history (person_id, change, version, actor, after)
+        VALUES (NEW.id, 'create', NEW.version, NULLIF(current_setting('demography.actor', true), ''), to_jsonb(NEW));
+    ELSIF TG_OP = 'DELETE' THEN
+        INSERT INTO people_history (person_id, change, version, actor, before)
+        VALUES (OLD.id, 'purge', OLD.version, NULLIF(current_setting('demography.actor', true), ''), to_jsonb(OLD));
+    ELSE
+        INSERT INTO people_history (person_id, change, version, actor, before, after)
+        VALUES (NEW.id,
+            CASE
+                WHEN OLD.deleted_at IS NULL AND NEW.deleted_at IS NOT NULL THEN 'delete'
+                WHEN OLD.deleted_at IS NOT NULL AND NEW.deleted_at IS NULL THEN 'restore'
+                ELSE 'update'
+            END::people_change,
+            NEW.version, NULLIF(current_setting('demography.actor', true), ''), to_jsonb(OLD), to_jsonb(NEW));
+    END IF;
+
+    RETURN NULL;
+END;
+$$;
+
+CREATE TRIGGER people_history
+    AFTER INSERT OR UPDATE OR DELETE ON people
+    FOR EACH ROW
+    EXECUTE FUNCTION record_people_history();
+
+-- history of existing data starts from its current state
+INSERT INTO people_history (person_id, change, version, after)
+SELECT id, 'create', version, to_jsonb(people) FROM people;
@@ -0,0 +1,12 @@
+-- Only SHA-256 hashes of keys are stored, plain keys are shown once at creation.
+CREATE TABLE api_keys (
+    id bigserial PRIMARY KEY,
+    key_name varchar(100) NOT NULL,
+    key_hash bytea NOT NULL UNIQUE,
+    scopes varchar(50)[] NOT NULL DEFAULT '{}',
+    created_at timestamptz NOT NULL DEFAULT now(),
+    revoked_at timestamptz
+);
+
+-- name of revoked key can be reused
+CREATE UNIQUE INDEX api_keys_key_name_idx ON api_keys (key_name) WHERE revoked_at IS NULL;
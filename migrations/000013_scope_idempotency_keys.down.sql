@@ -0,0 +1,8 @@
+-- The same key of different clients can't be kept.
+DELETE FROM idempotency_keys;
+
+ALTER TABLE idempotency_keys
+    DROP CONSTRAINT idempotency_keys_pkey,
+    DROP COLUMN client,
+    DROP COLUMN locked_until,
+    ADD PRIMARY KEY (idempotency_key);
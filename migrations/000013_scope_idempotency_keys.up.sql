@@ -0,0 +1,11 @@
+-- Keys are scoped per client, reservations of requests that never completed expire after a short lock.
+ALTER TABLE idempotency_keys
+    ADD COLUMN client varchar(255) NOT NULL DEFAULT '',
+    ADD COLUMN locked_until timestamptz;
+
+UPDATE idempotency_keys SET locked_until = now() WHERE status_code IS NULL;
+
+ALTER TABLE idempotency_keys
+    DROP CONSTRAINT idempotency_keys_pkey,
+    ADD PRIMARY KEY (client, idempotency_key),
+    ALTER COLUMN client DROP DEFAULT;
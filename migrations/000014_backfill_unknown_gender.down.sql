@@ -0,0 +1 @@
+-- backfilled values are valid for previous versions, nothing to do
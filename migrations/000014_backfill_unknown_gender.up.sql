@@ -0,0 +1,8 @@
+-- gender is known to be unknown since 000007: undetermined gender is unknown without source,
+-- declared one (including unknown) keeps 'declared' source
+-- not a change of person data, version and history are kept (NULL in snapshots is read as unknown)
+ALTER TABLE people DISABLE TRIGGER people_version;
+ALTER TABLE people DISABLE TRIGGER people_history;
+UPDATE people SET gender = 'unknown', gender_source = NULL WHERE gender IS NULL;
+ALTER TABLE people ENABLE TRIGGER people_history;
+ALTER TABLE people ENABLE TRIGGER people_version;
@@ -0,0 +1,25 @@
+-- redacted history can't be restored, only snapshots of further purges are kept again
+CREATE OR REPLACE FUNCTION record_people_history() RETURNS trigger
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        INSERT INTO people_history (person_id, change, version, actor, after)
+        VALUES (NEW.id, 'create', NEW.version, NULLIF(current_setting('demography.actor', true), ''), to_jsonb(NEW));
+    ELSIF TG_OP = 'DELETE' THEN
+        INSERT INTO people_history (person_id, change, version, actor, before)
+        VALUES (OLD.id, 'purge', OLD.version, NULLIF(current_setting('demography.actor', true), ''), to_jsonb(OLD));
+    ELSE
+        INSERT INTO people_history (person_id, change, version, actor, before, after)
+        VALUES (NEW.id,
+            CASE
+                WHEN OLD.deleted_at IS NULL AND NEW.deleted_at IS NOT NULL THEN 'delete'
+                WHEN OLD.deleted_at IS NOT NULL AND NEW.deleted_at IS NULL THEN 'restore'
+                ELSE 'update'
+            END::people_change,
+            NEW.version, NULLIF(current_setting('demography.actor', true), ''), to_jsonb(OLD), to_jsonb(NEW));
+    END IF;
+
+    RETURN NULL;
+END;
+$$;
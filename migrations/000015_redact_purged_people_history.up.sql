@@ -0,0 +1,30 @@
+-- Purge removes person data from history as well, only the facts of changes are kept.
+CREATE OR REPLACE FUNCTION record_people_history() RETURNS trigger
+LANGUAGE plpgsql
+AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        INSERT INTO people_history (person_id, change, version, actor, after)
+        VALUES (NEW.id, 'create', NEW.version, NULLIF(current_setting('demography.actor', true), ''), to_jsonb(NEW));
+    ELSIF TG_OP = 'DELETE' THEN
+        UPDATE people_history SET before = NULL, after = NULL WHERE person_id = OLD.id;
+
+        INSERT INTO people_history (person_id, change, version, actor)
+        VALUES (OLD.id, 'purge', OLD.version, NULLIF(current_setting('demography.actor', true), ''));
+    ELSE
+        INSERT INTO people_history (person_id, change, version, actor, before, after)
+        VALUES (NEW.id,
+            CASE
+                WHEN OLD.deleted_at IS NULL AND NEW.deleted_at IS NOT NULL THEN 'delete'
+                WHEN OLD.deleted_at IS NOT NULL AND NEW.deleted_at IS NULL THEN 'restore'
+                ELSE 'update'
+            END::people_change,
+            NEW.version, NULLIF(current_setting('demography.actor', true), ''), to_jsonb(OLD), to_jsonb(NEW));
+    END IF;
+
+    RETURN NULL;
+END;
+$$;
+
+UPDATE people_history SET before = NULL, after = NULL
+WHERE person_id IN (SELECT person_id FROM people_history WHERE change = 'purge');
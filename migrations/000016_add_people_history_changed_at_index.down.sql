@@ -0,0 +1 @@
+DROP INDEX people_history_changed_at_idx;
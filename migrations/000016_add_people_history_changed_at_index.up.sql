@@ -0,0 +1,2 @@
+-- people table at the moment is restored from snapshots changed before it, start of history is their minimum
+CREATE INDEX people_history_changed_at_idx ON people_history (changed_at) INCLUDE (person_id, id);
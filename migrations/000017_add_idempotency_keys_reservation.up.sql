@@ -0,0 +1,3 @@
+-- Response is saved (or key is released) only by the request holding the reservation,
+-- not by the one whose reservation has been taken over after the lock.
+ALTER TABLE idempotency_keys ADD COLUMN reservation bytea;